@@ -0,0 +1,139 @@
+package skreader
+
+import (
+	"strings"
+)
+
+// DeviceCapabilities represents features supported by connected device.
+// It is detected once when device is connected, see Device.Capabilities.
+type DeviceCapabilities struct {
+	Model        string
+	Firmware     int          // main firmware version
+	FirmwareInfo FirmwareInfo // all firmware components, set only when detected from connected device
+
+	MeasurementConfiguration         bool              // measuring mode and shutter speed can be set remotely
+	ExtendedMeasurementConfiguration bool              // field of view and exposure time can be set remotely
+	MeasuringModes                   []SkMeasuringMode // measuring modes available in remote control mode
+	FieldsOfView                     []SkFieldOfView   // empty if field of view can't be set remotely
+	ExposureTimes                    []SkExposureTime  // empty if exposure time can't be set remotely
+	ExtendedData                     bool              // measurement result contains extended data (TM-30, SSI, TLCI)
+	TM30                             bool              // TM-30 values are available in measurement result
+}
+
+// modelProfile describes capabilities of one device model.
+type modelProfile struct {
+	measurementConfiguration bool
+	measuringModes           []SkMeasuringMode
+	extendedMinFirmware      int // minimal firmware version with extended configuration and data, 0 if not supported
+}
+
+var (
+	allMeasuringModes = []SkMeasuringMode{
+		SkMeasuringModeAmbient,
+		SkMeasuringModeCordlessFlash,
+		SkMeasuringModeCordFlash,
+	}
+
+	// modelProfiles contains capabilities of known models.
+	// Tested C-700, C-800, C-7000 models.
+	modelProfiles = map[string]modelProfile{
+		"C-700": {
+			measurementConfiguration: false,
+			measuringModes:           []SkMeasuringMode{SkMeasuringModeAmbient},
+			extendedMinFirmware:      0,
+		},
+		"C-800": {
+			measurementConfiguration: true,
+			measuringModes:           allMeasuringModes,
+			extendedMinFirmware:      0,
+		},
+		"C-800-U": {
+			measurementConfiguration: true,
+			measuringModes:           allMeasuringModes,
+			extendedMinFirmware:      0,
+		},
+		"C-7000": {
+			measurementConfiguration: true,
+			measuringModes:           allMeasuringModes,
+			extendedMinFirmware:      26,
+		},
+	}
+
+	// unknownModelProfile is used for models which are not known. It only allows single ambient
+	// measurement with the configuration set on the device itself, which works on all models.
+	unknownModelProfile = modelProfile{
+		measurementConfiguration: false,
+		measuringModes:           []SkMeasuringMode{SkMeasuringModeAmbient},
+		extendedMinFirmware:      0,
+	}
+)
+
+// NewDeviceCapabilities returns capabilities of given device model and main firmware version.
+// Regional variants of known models (e.g. "C-7000-U") get capabilities of the base model.
+// Unknown models get minimal capabilities.
+func NewDeviceCapabilities(model string, firmware int) DeviceCapabilities {
+	return newDeviceCapabilities(model, firmware, findModelProfile(model))
+}
+
+func newDeviceCapabilities(model string, firmware int, profile modelProfile) DeviceCapabilities {
+	extended := profile.extendedMinFirmware > 0 && firmware >= profile.extendedMinFirmware
+
+	caps := DeviceCapabilities{
+		Model:                            model,
+		Firmware:                         firmware,
+		FirmwareInfo:                     FirmwareInfo{Components: nil, main: 0},
+		MeasurementConfiguration:         profile.measurementConfiguration,
+		ExtendedMeasurementConfiguration: extended,
+		MeasuringModes:                   profile.measuringModes,
+		FieldsOfView:                     nil,
+		ExposureTimes:                    nil,
+		ExtendedData:                     extended,
+		TM30:                             extended,
+	}
+	if extended {
+		caps.FieldsOfView = []SkFieldOfView{SkFieldOfView2Deg, SkFieldOfView10Deg}
+		caps.ExposureTimes = []SkExposureTime{SkExposureTimeAuto, SkExposureTime100Msec, SkExposureTime1Sec}
+	}
+
+	return caps
+}
+
+// findModelProfile returns profile of the model. If model is not known, the profile of the longest
+// known model name which is followed by "-" in the given one is used. Otherwise unknownModelProfile is returned.
+func findModelProfile(model string) modelProfile {
+	if profile, ok := modelProfiles[model]; ok {
+		return profile
+	}
+
+	found := unknownModelProfile
+	foundLen := 0
+	for name, profile := range modelProfiles {
+		if strings.HasPrefix(model, name+"-") && len(name) > foundLen {
+			found = profile
+			foundLen = len(name)
+		}
+	}
+
+	return found
+}
+
+// clone returns deep copy of capabilities, so that it can be used while device is reconnected.
+func (c DeviceCapabilities) clone() DeviceCapabilities {
+	c.FirmwareInfo.Components = append([]FirmwareComponent(nil), c.FirmwareInfo.Components...)
+	c.MeasuringModes = append([]SkMeasuringMode(nil), c.MeasuringModes...)
+	c.FieldsOfView = append([]SkFieldOfView(nil), c.FieldsOfView...)
+	c.ExposureTimes = append([]SkExposureTime(nil), c.ExposureTimes...)
+
+	return c
+}
+
+// SupportsMeasuringMode reports whether measuring mode is available in remote control mode.
+func (c DeviceCapabilities) SupportsMeasuringMode(mode SkMeasuringMode) bool {
+	for _, m := range c.MeasuringModes {
+		if m == mode {
+			return true
+		}
+	}
+
+	return false
+}
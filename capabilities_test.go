@@ -0,0 +1,53 @@
+package skreader_test
+
+import (
+	"testing"
+
+	"github.com/akares/skreader"
+)
+
+func TestNewDeviceCapabilities(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		model         string
+		firmware      int
+		wantConfig    bool
+		wantExtended  bool
+		wantFlashMode bool
+	}{
+		{name: "C-700", model: "C-700", firmware: 27, wantConfig: false, wantExtended: false, wantFlashMode: false},
+		{name: "C-800", model: "C-800", firmware: 27, wantConfig: true, wantExtended: false, wantFlashMode: true},
+		{name: "C-800-U", model: "C-800-U", firmware: 27, wantConfig: true, wantExtended: false, wantFlashMode: true},
+		{name: "C-7000 old firmware", model: "C-7000", firmware: 25, wantConfig: true, wantExtended: false, wantFlashMode: true},
+		{name: "C-7000", model: "C-7000", firmware: 26, wantConfig: true, wantExtended: true, wantFlashMode: true},
+		{name: "C-7000 regional variant", model: "C-7000-U", firmware: 27, wantConfig: true, wantExtended: true, wantFlashMode: true},
+		{name: "unknown model", model: "C-12345", firmware: 99, wantConfig: false, wantExtended: false, wantFlashMode: false},
+		{name: "empty model", model: "", firmware: 0, wantConfig: false, wantExtended: false, wantFlashMode: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c := skreader.NewDeviceCapabilities(tt.model, tt.firmware)
+
+			if c.Model != tt.model || c.Firmware != tt.firmware {
+				t.Errorf("Model, Firmware = %s, %d, want %s, %d", c.Model, c.Firmware, tt.model, tt.firmware)
+			}
+			if c.MeasurementConfiguration != tt.wantConfig {
+				t.Errorf("MeasurementConfiguration = %v, want %v", c.MeasurementConfiguration, tt.wantConfig)
+			}
+			if c.ExtendedMeasurementConfiguration != tt.wantExtended {
+				t.Errorf("ExtendedMeasurementConfiguration = %v, want %v", c.ExtendedMeasurementConfiguration, tt.wantExtended)
+			}
+			if c.ExtendedData != tt.wantExtended || c.TM30 != tt.wantExtended {
+				t.Errorf("ExtendedData, TM30 = %v, %v, want %v", c.ExtendedData, c.TM30, tt.wantExtended)
+			}
+			if (len(c.FieldsOfView) > 0) != tt.wantExtended || (len(c.ExposureTimes) > 0) != tt.wantExtended {
+				t.Errorf("FieldsOfView, ExposureTimes = %v, %v, want configurable %v", c.FieldsOfView, c.ExposureTimes, tt.wantExtended)
+			}
+			if !c.SupportsMeasuringMode(skreader.SkMeasuringModeAmbient) {
+				t.Errorf("SupportsMeasuringMode(ambient) = false, want true")
+			}
+			if got := c.SupportsMeasuringMode(skreader.SkMeasuringModeCordFlash); got != tt.wantFlashMode {
+				t.Errorf("SupportsMeasuringMode(cord flash) = %v, want %v", got, tt.wantFlashMode)
+			}
+		})
+	}
+}
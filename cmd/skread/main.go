@@ -11,6 +11,8 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"regexp"
+	"strings"
 	"syscall"
 	"time"
 
@@ -41,50 +43,199 @@ type JSONResponse struct {
 	Measurements []skreader.MeasurementJSON `json:"Measurements"`
 }
 
+type StatusResponse struct {
+	Connected bool                     `json:"Connected"` // whether device to be used for measurements is connected
+	Devices   []skreader.UsbDeviceInfo `json:"Devices"`
+}
+
 type SPDXResponse struct {
 	XMLName              xml.Name                          `xml:"IESTM2714"`
 	Header               skreader.SPDXHeader               `xml:"Header"`
 	SpectralDistribution skreader.SPDXSpectralDistribution `xml:"SpectralDistribution"`
 }
 
-func skConnect() (*skreader.Device, error) {
-	sk, err := skreader.NewDeviceWithAdapter(&skreader.GousbAdapter{})
+// USB options set by global flags.
+var (
+	usbTimeout      time.Duration // timeout of one USB transfer
+	reconnects      int           // how many times to try to reconnect the device after USB transfer error
+	measureTimeout  time.Duration // how long to wait for the device to end measuring
+	debug           bool          // print every command sent to the device and every status poll
+	deviceSerial    string        // device with this serial number is used if set
+	devicePath      string        // device connected to this USB port is used if set
+	remoteAddr      string        // device served by `serve-usb` command at this address is used if set
+	token           string        // shared secret of `serve-usb` command and its clients
+	recordTrace     *os.File      // USB traffic is recorded to this file if set
+	replayTracePath string        // USB traffic is replayed from this file instead of using the device if set
+)
+
+// skConnect connects to the device using adapter created by newAdapter.
+func skConnect(isFakeDevice bool) (*skreader.Device, error) {
+	adapter, err := newAdapter(isFakeDevice)
 	if err != nil {
 		return nil, err
 	}
 
-	return sk, nil
+	opts := []skreader.DeviceOption{skreader.WithMeasureTimeout(measureTimeout)}
+	if debug {
+		logger := log.New(os.Stderr, "debug: ", log.Ltime|log.Lmicroseconds)
+		opts = append(opts, skreader.WithLogger(logger), skreader.WithTracer(skreader.NewLogTracer(logger)))
+	}
+	if reconnects > 0 {
+		policy := skreader.NewReconnectPolicy()
+		policy.Attempts = reconnects
+		opts = append(opts, skreader.WithReconnect(policy))
+	}
+
+	return skreader.NewDevice(adapter, opts...)
 }
 
-// infoCmd shows info about the connected device.
-func infoCmd(c *cli.Context) error {
-	if c.Bool("fake-device") {
-		fmt.Println("Fake device")
+// newAdapter creates adapter for the device selected by global flags. If isFakeDevice is true,
+// simulated device is used instead of real one. If remote device is requested, it is used over network.
+// If trace replay is requested, recorded device session is played back instead.
+func newAdapter(isFakeDevice bool) (skreader.UsbAdapter, error) {
+	var adapter skreader.UsbAdapter = skreader.NewDefaultAdapter(skreader.UsbAdapterOptions{
+		ReadTimeout:  usbTimeout,
+		WriteTimeout: usbTimeout,
+		Serial:       deviceSerial,
+		Path:         devicePath,
+	})
+	if remoteAddr != "" {
+		adapter = skreader.NewNetworkAdapter(remoteAddr, skreader.NetworkAdapterOptions{
+			Token:   token,
+			Timeout: 0,
+		})
+	}
+	if isFakeDevice {
+		sim := skreader.NewSimulatedDevice("C-7000")
+		sim.FlashDelay = time.Second
+		adapter = sim
+	}
+
+	if replayTracePath != "" {
+		replay, err := openReplayTrace(replayTracePath)
+		if err != nil {
+			return nil, err
+		}
+		adapter = replay
+	}
+
+	if recordTrace != nil {
+		adapter = skreader.NewRecordingAdapter(adapter, recordTrace)
+	}
+
+	return adapter, nil
+}
+
+// openReplayTrace reads the trace file recorded with --record flag.
+func openReplayTrace(path string) (*skreader.ReplayAdapter, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return skreader.NewReplayAdapter(f)
+}
+
+// setupGlobals handles global USB and trace flags.
+func setupGlobals(c *cli.Context) error {
+	usbTimeout = c.Duration("usb-timeout")
+	reconnects = c.Int("reconnect")
+	measureTimeout = c.Duration("measure-timeout")
+	debug = c.Bool("debug")
+	deviceSerial, devicePath = parseDeviceSelector(c.String("device"))
+	remoteAddr = c.String("remote")
+	token = c.String("token")
+	replayTracePath = c.String("replay")
+
+	if path := c.String("record"); path != "" {
+		// Concurrent web requests would interleave in one trace, which can't be replayed then.
+		if c.Args().First() == "webserver" {
+			return errors.New("--record can't be used with webserver command")
+		}
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644) //nolint:gomnd
+		if err != nil {
+			return fmt.Errorf("could not open trace file: %w", err)
+		}
+		recordTrace = f
+	}
+
+	return nil
+}
+
+// closeTraces closes the trace file opened by setupGlobals.
+func closeTraces(_ *cli.Context) error {
+	if recordTrace == nil {
+		return nil
+	}
+
+	return recordTrace.Close()
+}
+
+// parseDeviceSelector tells whether --device flag value is USB port path (e.g. "1-2.3") or serial number.
+func parseDeviceSelector(device string) (serial, path string) {
+	if usbPathRe.MatchString(device) {
+		return "", device
+	}
+
+	return device, ""
+}
+
+var usbPathRe = regexp.MustCompile(`^\d+-\d+(\.\d+)*$`)
+
+// listCmd shows all connected devices.
+func listCmd(_ *cli.Context) error {
+	devs, err := skreader.ListDevices()
+	if err != nil {
+		return err
+	}
+	if len(devs) == 0 {
+		fmt.Println("No devices found.")
 
 		return nil
 	}
 
-	sk, err := skConnect()
+	for _, dev := range devs {
+		serial := dev.Serial
+		if serial == "" {
+			serial = "-"
+		}
+		fmt.Printf("%-10s bus %03d address %03d serial %-12s %s %s\n",
+			dev.Path, dev.Bus, dev.Address, serial, dev.Manufacturer, dev.Product)
+	}
+
+	return nil
+}
+
+// infoCmd shows info about the connected device.
+func infoCmd(c *cli.Context) error {
+	sk, err := skConnect(c.Bool("fake-device"))
 	if err != nil {
 		return err
 	}
 	defer sk.Close()
 
-	st, err := sk.State()
+	st, err := sk.StateContext(c.Context)
 	if err != nil {
 		return err
 	}
 
-	model, _ := sk.ModelName()
-	fw, _ := sk.FirmwareVersion()
+	caps := sk.Capabilities()
 
 	fmt.Println("Device:", sk.String())
-	fmt.Println("Model:", model)
-	fmt.Println("Firmware:", fw)
+	fmt.Println("Model:", caps.Model)
+	fmt.Println("Firmware:", caps.Firmware)
+	for i, fw := range caps.FirmwareInfo.Components {
+		fmt.Printf("Firmware component %d: version %02d, checksum %s\n", i+1, fw.Version, fw.Checksum)
+	}
 	fmt.Println("Status:", st.Status)
 	fmt.Println("Remote:", st.Remote)
 	fmt.Println("Button:", st.Button, st.Ring)
 	fmt.Println("Ring:", st.Ring)
+	fmt.Println("Measurement configuration:", caps.MeasurementConfiguration)
+	fmt.Println("Extended measurement configuration:", caps.ExtendedMeasurementConfiguration)
+	fmt.Println("Measuring modes:", caps.MeasuringModes)
+	fmt.Println("TM-30:", caps.TM30)
 
 	return nil
 }
@@ -94,7 +245,7 @@ func jsonCmd(c *cli.Context) error {
 	measName := c.String("name")
 	measNote := c.String("note")
 
-	response, err := measureAsJSON(c.Bool("fake-device"), measName, measNote)
+	response, err := measureAsJSON(c.Context, c.Bool("fake-device"), measName, measNote)
 	if err != nil {
 		fmt.Println("Measurement error:", err)
 	}
@@ -116,7 +267,7 @@ func spdxCmd(c *cli.Context) error {
 	measName := c.String("name")
 	measNote := c.String("note")
 
-	response, err := measureAsSPDX(c.Bool("fake-device"), measName, measNote)
+	response, err := measureAsSPDX(c.Context, c.Bool("fake-device"), measName, measNote)
 	if err != nil {
 		fmt.Println("Measurement error:", err)
 	}
@@ -136,9 +287,22 @@ func spdxCmd(c *cli.Context) error {
 // The `/measure` endpoint triggers a measurement and returns the result as JSON.
 // The `fake` query parameter can be used to trigger a measurement with a fake device response (for testing purpose).
 // The `name` and `note` query parameters set the measurement name and note fields.
+// The `/status` endpoint returns connected devices as JSON.
+// Device is reconnected after USB transfer errors (e.g. when it is replugged) unless `--reconnect 0` is set.
 //
-//nolint:funlen
+//nolint:funlen,gocognit
 func webserverCmd(c *cli.Context) error {
+	// Survive device replug by default.
+	if !c.IsSet("reconnect") {
+		reconnects = skreader.ReconnectAttemptsDefault
+	}
+
+	watcher := &skreader.DeviceWatcher{} //nolint:exhaustruct
+	if replayTracePath != "" || remoteAddr != "" {
+		watcher.List = listNonUsbDevices
+	}
+	go logDeviceEvents(watcher.Watch(c.Context))
+
 	mux := http.NewServeMux()
 
 	mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
@@ -150,6 +314,25 @@ func webserverCmd(c *cli.Context) error {
 		// Spdx
 		fmt.Fprint(w, "<li><a href='/measureSpdx?name=The Name&note=The Note'>Measure Spdx</a></li>")
 		fmt.Fprint(w, "<li><a href='/measureSpdx?name=The Name&note=The Note&fake=1'>Measure Spdx (fake device)</a></li>")
+		fmt.Fprint(w, "</br>")
+		// Status
+		fmt.Fprint(w, "<li><a href='/status'>Connected devices</a></li>")
+	})
+
+	mux.HandleFunc("/status", func(w http.ResponseWriter, _ *http.Request) {
+		devs := watcher.Devices()
+		response := StatusResponse{
+			Connected: isDeviceConnected(devs),
+			Devices:   devs,
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(response); err != nil {
+			fmt.Println("Error encoding JSON:", err)
+		}
 	})
 
 	mux.HandleFunc("/measureJson", func(w http.ResponseWriter, r *http.Request) {
@@ -159,9 +342,15 @@ func webserverCmd(c *cli.Context) error {
 		measName := query.Get("name")
 		measNote := query.Get("note")
 
+		if !isFakeDevice && !isDeviceConnected(watcher.Devices()) {
+			http.Error(w, "no meter connected", http.StatusServiceUnavailable)
+
+			return
+		}
+
 		w.Header().Set("Content-Type", "application/json")
 
-		response, err := measureAsJSON(isFakeDevice, measName, measNote)
+		response, err := measureAsJSON(r.Context(), isFakeDevice, measName, measNote)
 		if err != nil {
 			fmt.Println("Measurement error:", err)
 		}
@@ -180,9 +369,15 @@ func webserverCmd(c *cli.Context) error {
 		measName := query.Get("name")
 		measNote := query.Get("note")
 
+		if !isFakeDevice && !isDeviceConnected(watcher.Devices()) {
+			http.Error(w, "no meter connected", http.StatusServiceUnavailable)
+
+			return
+		}
+
 		w.Header().Set("Content-Type", "application/xml")
 
-		response, err := measureAsSPDX(isFakeDevice, measName, measNote)
+		response, err := measureAsSPDX(r.Context(), isFakeDevice, measName, measNote)
 		if err != nil {
 			fmt.Println("Measurement error:", err)
 		}
@@ -211,12 +406,6 @@ func webserverCmd(c *cli.Context) error {
 		log.Fatal("HTTP server listen: ", err)
 	}
 
-	// Setup shutdown signal.
-	ctxWithCancel, cancel := context.WithCancel(context.Background())
-	sig := make(chan os.Signal, 1)
-	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
-	go func() { <-sig; cancel() }()
-
 	go func() {
 		if err = srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
 			log.Fatal("HTTP server serve: ", err)
@@ -227,7 +416,7 @@ func webserverCmd(c *cli.Context) error {
 	fmt.Println("Press ctrl+c to stop.")
 
 	// Wait for shutdown (ctrl-c).
-	<-ctxWithCancel.Done()
+	<-c.Context.Done()
 
 	// Clean up.
 
@@ -243,30 +432,308 @@ func webserverCmd(c *cli.Context) error {
 	return nil
 }
 
+// listNonUsbDevices lists the device used instead of local USB ones with --replay or --remote flag.
+func listNonUsbDevices() ([]skreader.UsbDeviceInfo, error) {
+	path := "replay"
+	if replayTracePath == "" {
+		path = "remote " + remoteAddr
+	}
+
+	return []skreader.UsbDeviceInfo{
+		{Bus: 0, Address: 0, Path: path, Serial: "", Manufacturer: "", Product: ""},
+	}, nil
+}
+
+// logDeviceEvents prints device attach and detach events until events channel is closed.
+func logDeviceEvents(events <-chan skreader.DeviceEvent) {
+	for ev := range events {
+		fmt.Printf("🔌 Device %s: %s %s (%s)\n", ev.Type, ev.Device.Manufacturer, ev.Device.Product, ev.Device.Path)
+	}
+}
+
+// isDeviceConnected reports whether devs contain device selected with --device flag (or any device if not set).
+// Serial number is unknown if device could not be opened while listing (e.g. it is measuring right now),
+// such device is considered to be the selected one.
+func isDeviceConnected(devs []skreader.UsbDeviceInfo) bool {
+	for _, dev := range devs {
+		if devicePath != "" && dev.Path != devicePath {
+			continue
+		}
+		if deviceSerial != "" && dev.Serial != "" && dev.Serial != deviceSerial {
+			continue
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// serveUsbCmd exposes the device to `--remote` clients on other hosts until ctrl+c is pressed.
+func serveUsbCmd(c *cli.Context) error {
+	if token == "" {
+		return errors.New("token is required, use --token flag or SKREAD_TOKEN environment variable")
+	}
+
+	adapter, err := newAdapter(c.Bool("fake-device"))
+	if err != nil {
+		return err
+	}
+
+	addr := net.JoinHostPort(c.String("address"), c.String("port"))
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	srv := &skreader.UsbServer{
+		Adapter: adapter,
+		Token:   token,
+		Log:     log.New(os.Stdout, "", log.LstdFlags),
+	}
+	go func() {
+		<-c.Context.Done()
+		_ = ln.Close()
+	}()
+
+	fmt.Printf("🚀 USB server started at %s\n", addr)
+	fmt.Println("Press ctrl+c to stop.")
+
+	if err = srv.Serve(ln); c.Context.Err() == nil {
+		return err
+	}
+
+	fmt.Println("👋 Bye.")
+
+	return nil
+}
+
 // measureCmd runs a measurement and outputs the selected data.
-//
-//nolint:gocyclo,funlen
 func measureCmd(c *cli.Context) error {
-	var meas *skreader.Measurement
-	var err error
+	mode, err := parseMeasuringMode(c.String("mode"))
+	if err != nil {
+		return err
+	}
 
-	if c.Bool("fake-device") {
-		meas, err = skreader.NewMeasurementFromBytes(skreader.Testdata)
-		if err != nil {
-			return err
+	err = checkMeasureFlags(c, mode)
+	if err != nil {
+		return err
+	}
+
+	if mode.IsFlash() {
+		return measureFlashCmd(c, mode)
+	}
+
+	if c.Bool("continuous") {
+		return measureContinuousCmd(c)
+	}
+
+	if c.IsSet("repeat") {
+		return measureRepeatCmd(c)
+	}
+
+	sk, err := skConnect(c.Bool("fake-device"))
+	if err != nil {
+		return err
+	}
+	defer sk.Close()
+
+	meas, err := sk.MeasureContext(c.Context)
+	if err != nil {
+		return err
+	}
+
+	printMeasurement(c, meas, false)
+
+	return nil
+}
+
+// checkMeasureFlags refuses flags which can't be used together instead of ignoring some of them.
+func checkMeasureFlags(c *cli.Context, mode skreader.SkMeasuringMode) error {
+	switch {
+	case mode.IsFlash() && c.Bool("continuous"):
+		return fmt.Errorf("--continuous can't be used with %s measuring mode", c.String("mode"))
+	case mode.IsFlash() && c.IsSet("repeat"):
+		return fmt.Errorf("--repeat can't be used with %s measuring mode", c.String("mode"))
+	case c.Bool("continuous") && c.IsSet("repeat"):
+		return errors.New("--continuous and --repeat can't be used together")
+	case c.IsSet("repeat") && c.Int("repeat") < 1:
+		return fmt.Errorf("--repeat must be at least 1, got %d", c.Int("repeat"))
+	case !c.IsSet("repeat") && c.IsSet("json"):
+		return errors.New("--json can be used only with --repeat")
+	case !c.IsSet("repeat") && c.IsSet("interval"):
+		return errors.New("--interval can be used only with --repeat")
+	}
+
+	return nil
+}
+
+// measureContinuousCmd runs measurements one after another until ctrl-c and outputs the selected data of each one.
+func measureContinuousCmd(c *cli.Context) error {
+	sk, err := skConnect(c.Bool("fake-device"))
+	if err != nil {
+		return err
+	}
+	defer sk.Close()
+
+	events, err := sk.MeasureContinuous(c.Context)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Measuring continuously, press ctrl+c to stop.")
+
+	for ev := range events {
+		fmt.Println("============", ev.Time.Format(time.RFC3339))
+		if ev.Dropped > 0 {
+			fmt.Printf("(%d measurements skipped)\n", ev.Dropped)
 		}
-	} else {
-		var sk *skreader.Device
-		sk, err = skConnect()
-		if err != nil {
-			return err
+		if ev.Err != nil {
+			fmt.Println("Measurement error:", ev.Err)
+
+			continue
 		}
-		defer sk.Close()
+		printMeasurement(c, ev.Measurement, false)
+	}
+
+	return nil
+}
 
-		meas, err = sk.Measure()
+// measureRepeatCmd runs several measurements and outputs statistics of the selected data.
+func measureRepeatCmd(c *cli.Context) error {
+	sk, err := skConnect(c.Bool("fake-device"))
+	if err != nil {
+		return err
+	}
+	defer sk.Close()
+
+	stats, err := sk.MeasureNContext(c.Context, c.Int("repeat"), c.Duration("interval"))
+	if err != nil {
+		return err
+	}
+
+	if c.Bool("json") {
+		file, err := json.MarshalIndent(skreader.NewJSONMeasurementStats(stats, time.Now()), "", "  ")
 		if err != nil {
 			return err
 		}
+		fmt.Println(string(file))
+
+		return nil
+	}
+
+	printMeasurementStats(c, stats)
+
+	return nil
+}
+
+// printMeasurementStats outputs statistics of the measurement data selected by command flags.
+func printMeasurementStats(c *cli.Context, stats *skreader.MeasurementStats) {
+	printStats := func(label string, s skreader.Stats) {
+		if s.N == 0 {
+			fmt.Printf("%s n/a (%d out of range)\n", label, s.Excluded)
+
+			return
+		}
+		fmt.Printf("%s mean %.6g, stddev %.6g, min %.6g, max %.6g", label, s.Mean, s.StdDev, s.Min, s.Max)
+		if s.Excluded > 0 {
+			fmt.Printf(" (%d out of range excluded)", s.Excluded)
+		}
+		fmt.Println()
+	}
+
+	fmt.Println("Measurements:", len(stats.Measurements))
+	printStats("LUX:", stats.Lux)
+	printStats("Fc:", stats.FootCandle)
+	printStats("CCT:", stats.CCT)
+	printStats("CCT DeltaUv:", stats.DeltaUv)
+	printStats("x:", stats.X)
+	printStats("y:", stats.Y)
+	printStats("RA:", stats.Ra)
+
+	if c.Bool("spectra1nm") || c.Bool("all") {
+		fmt.Println("SpectralData 1nm (wavelength,mean,stddev,min,max):")
+		for i, s := range &stats.SpectralData1nm {
+			fmt.Printf("%d,%f,%f,%f,%f\n", 380+i, s.Mean, s.StdDev, s.Min, s.Max)
+		}
+	}
+	if c.Bool("spectra5nm") || c.Bool("all") {
+		fmt.Println("SpectralData 5nm (wavelength,mean,stddev,min,max):")
+		for i, s := range &stats.SpectralData5nm {
+			fmt.Printf("%d,%f,%f,%f,%f\n", 380+i*5, s.Mean, s.StdDev, s.Min, s.Max)
+		}
+	}
+}
+
+// measureFlashCmd runs a flash measurement and outputs the selected data.
+func measureFlashCmd(c *cli.Context, mode skreader.SkMeasuringMode) error {
+	sk, err := skConnect(c.Bool("fake-device"))
+	if err != nil {
+		return err
+	}
+	defer sk.Close()
+
+	sk.MeasurementConfig.MeasuringMode = mode
+
+	if mode == skreader.SkMeasuringModeCordlessFlash {
+		fmt.Println("Waiting for the flash to be fired...")
+	}
+
+	flash, err := sk.MeasureFlashContext(c.Context)
+	if err != nil {
+		return err
+	}
+
+	// Flash result has the same data as ambient one except of illuminance units.
+	meas := &skreader.Measurement{ //nolint:exhaustruct
+		Header: flash.Header,
+		Illuminance: skreader.IlluminanceValue{
+			Lux:        flash.Illuminance.LuxSecond,
+			FootCandle: flash.Illuminance.FootCandleSecond,
+		},
+		Tristimulus:           flash.Tristimulus,
+		ColorTemperature:      flash.ColorTemperature,
+		CIE1931:               flash.CIE1931,
+		CIE1976:               flash.CIE1976,
+		DWL:                   flash.DWL,
+		PPFD:                  flash.PPFD,
+		ColorRenditionIndexes: flash.ColorRenditionIndexes,
+		SpectralData5nm:       flash.SpectralData5nm,
+		SpectralData1nm:       flash.SpectralData1nm,
+		PeakWavelength:        flash.PeakWavelength,
+		TM30:                  flash.TM30,
+		SSI:                   flash.SSI,
+		TLCI:                  flash.TLCI,
+		Config:                flash.Config,
+	}
+
+	printMeasurement(c, meas, true)
+
+	return nil
+}
+
+// parseMeasuringMode converts `--mode` flag value to measuring mode.
+func parseMeasuringMode(mode string) (skreader.SkMeasuringMode, error) {
+	switch mode {
+	case "", "ambient":
+		return skreader.SkMeasuringModeAmbient, nil
+	case "flash", "cordless-flash":
+		return skreader.SkMeasuringModeCordlessFlash, nil
+	case "cord-flash":
+		return skreader.SkMeasuringModeCordFlash, nil
+	default:
+		return 0, fmt.Errorf("unknown measuring mode: %s", mode)
+	}
+}
+
+// printMeasurement outputs the measurement data selected by command flags.
+// If flash is true, illuminance values are printed in Lux-second and foot-candle-second units.
+//
+//nolint:gocyclo,funlen
+func printMeasurement(c *cli.Context, meas *skreader.Measurement, flash bool) {
+	luxLabel, fcLabel := "LUX:", "Fc:"
+	if flash {
+		luxLabel, fcLabel = "LUX·s:", "Fc·s:"
 	}
 
 	verbose := c.Bool("verbose")
@@ -279,19 +746,28 @@ func measureCmd(c *cli.Context) error {
 	showDWL := c.Bool("dwl") || c.Bool("all") || c.Bool("simple")
 
 	showCRI := c.Bool("cri") || c.Bool("all")
+	showTM30 := c.Bool("tm30") || c.Bool("all")
+	showSSI := c.Bool("ssi") || c.Bool("all")
+	showTLCI := c.Bool("tlci") || c.Bool("all")
 	showSpectra1nm := c.Bool("spectra1nm") || c.Bool("all")
 	showSpectra5nm := c.Bool("spectra5nm") || c.Bool("all")
 
 	// Shown by default if no other flag is set
-	showLDi := c.Bool("ldi") || c.Bool("all") || (!showIlluminance && !showColorTemperature && !showTristimulus && !showCIE1931 && !showCIE1976 && !showDWL && !showCRI && !showSpectra1nm && !showSpectra5nm)
+	showLDi := c.Bool("ldi") || c.Bool("all") || (!showIlluminance && !showColorTemperature && !showTristimulus && !showCIE1931 && !showCIE1976 && !showDWL && !showCRI && !showTM30 && !showSSI && !showTLCI && !showSpectra1nm && !showSpectra5nm)
+
+	if verbose && meas.Config != nil {
+		if skipped := meas.Config.Skipped(); len(skipped) > 0 {
+			fmt.Println("Settings not applied (device settings used):", strings.Join(skipped, ", "))
+		}
+	}
 
 	if showIlluminance {
 		if verbose {
 			fmt.Println("------------")
 			fmt.Println("Illuminance:")
 		}
-		fmt.Println("LUX:", meas.Illuminance.Lux.Str)
-		fmt.Println("Fc:", meas.Illuminance.FootCandle)
+		fmt.Println(luxLabel, meas.Illuminance.Lux.Str)
+		fmt.Println(fcLabel, meas.Illuminance.FootCandle)
 	}
 
 	if showColorTemperature {
@@ -351,6 +827,49 @@ func measureCmd(c *cli.Context) error {
 		}
 	}
 
+	if showTM30 {
+		if verbose {
+			fmt.Println("------------")
+			fmt.Println("TM30:")
+		}
+		if meas.TM30 == nil {
+			fmt.Println("TM30: n/a")
+		} else {
+			fmt.Println("Rf:", meas.TM30.Rf)
+			fmt.Println("Rg:", meas.TM30.Rg)
+			for i := range meas.TM30.HueBinRf {
+				fmt.Printf("Hue bin %d: Rf=%s Rcs=%s%% Rhs=%s\n", i+1,
+					meas.TM30.HueBinRf[i], meas.TM30.HueBinRcs[i], meas.TM30.HueBinRhs[i])
+			}
+		}
+	}
+
+	if showSSI {
+		if verbose {
+			fmt.Println("------------")
+			fmt.Println("SSI:")
+		}
+		if meas.SSI == nil {
+			fmt.Println("SSI: n/a")
+		} else {
+			fmt.Println("SSIt:", meas.SSI.Tungsten)
+			fmt.Println("SSId:", meas.SSI.Daylight)
+		}
+	}
+
+	if showTLCI {
+		if verbose {
+			fmt.Println("------------")
+			fmt.Println("TLCI:")
+		}
+		if meas.TLCI == nil {
+			fmt.Println("TLCI: n/a")
+		} else {
+			fmt.Println("TLCI:", meas.TLCI.Qa)
+			fmt.Println("TLMF:", meas.TLCI.TLMF)
+		}
+	}
+
 	if showSpectra1nm {
 		if verbose {
 			fmt.Println("------------")
@@ -377,70 +896,47 @@ func measureCmd(c *cli.Context) error {
 		if verbose {
 			fmt.Println("------------")
 		}
-		fmt.Println("LUX:", meas.Illuminance.Lux.Str)
+		fmt.Println(luxLabel, meas.Illuminance.Lux.Str)
 		fmt.Println("CCT:", meas.ColorTemperature.Tcp)
 		fmt.Println("CCT DeltaUv:", meas.ColorTemperature.DeltaUv)
 		fmt.Println("RA:", meas.ColorRenditionIndexes.Ra)
 		fmt.Println("R9:", meas.ColorRenditionIndexes.Ri[8])
+		if meas.TLCI != nil {
+			fmt.Println("TLCI:", meas.TLCI.Qa)
+		}
 	}
-
-	return nil
 }
 
 // measureAsJSON runs a measurement and returns the result as JSON.
 // It is used by the `jsonCmd` and `webserverCmd` functions since they share the same functionality.
-func measureAsJSON(isFakeDevice bool, measName, measNote string) (*JSONResponse, error) {
-	var meas *skreader.Measurement
-	var err error
-
-	var response JSONResponse
-	if isFakeDevice {
-		meas, err = skreader.NewMeasurementFromBytes(skreader.Testdata)
-		if err != nil {
-			return nil, err
-		}
-		response = JSONResponse{
-			Device:       "fake-device",
-			Model:        "n/a",
-			Firmware:     "n/a",
-			Status:       "n/a",
-			Remote:       "n/a",
-			Button:       "n/a",
-			Ring:         "n/a",
-			Measurements: []skreader.MeasurementJSON{}, // populated later
-		}
-	} else {
-		var sk *skreader.Device
-		sk, err = skConnect()
-		if err != nil {
-			return nil, err
-		}
-		defer sk.Close()
-
-		meas, err = sk.Measure()
-		if err != nil {
-			return nil, err
-		}
+func measureAsJSON(ctx context.Context, isFakeDevice bool, measName, measNote string) (*JSONResponse, error) {
+	sk, err := skConnect(isFakeDevice)
+	if err != nil {
+		return nil, err
+	}
+	defer sk.Close()
 
-		var st *skreader.DeviceState
-		st, err = sk.State()
-		if err != nil {
-			return nil, err
-		}
+	meas, err := sk.MeasureContext(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-		model, _ := sk.ModelName()
-		fw, _ := sk.FirmwareVersion()
+	st, err := sk.StateContext(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-		response = JSONResponse{
-			Device:       sk.String(),
-			Model:        model,
-			Firmware:     fmt.Sprintf("%v", fw),
-			Status:       fmt.Sprintf("%v", st.Status),
-			Remote:       fmt.Sprintf("%v", st.Remote),
-			Button:       fmt.Sprintf("%v", st.Button),
-			Ring:         fmt.Sprintf("%v", st.Ring),
-			Measurements: []skreader.MeasurementJSON{}, // populated later
-		}
+	caps := sk.Capabilities()
+
+	response := JSONResponse{
+		Device:       sk.String(),
+		Model:        caps.Model,
+		Firmware:     fmt.Sprintf("%v", caps.Firmware),
+		Status:       fmt.Sprintf("%v", st.Status),
+		Remote:       fmt.Sprintf("%v", st.Remote),
+		Button:       fmt.Sprintf("%v", st.Button),
+		Ring:         fmt.Sprintf("%v", st.Ring),
+		Measurements: []skreader.MeasurementJSON{}, // populated later
 	}
 
 	measTime := time.Now()
@@ -451,34 +947,23 @@ func measureAsJSON(isFakeDevice bool, measName, measNote string) (*JSONResponse,
 	return &response, nil
 }
 
-func measureAsSPDX(isFakeDevice bool, measName, measNote string) (*SPDXResponse, error) {
-	var meas *skreader.Measurement
-	var err error
-
-	if isFakeDevice {
-		meas, err = skreader.NewMeasurementFromBytes(skreader.Testdata)
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		var sk *skreader.Device
-		sk, err = skConnect()
-		if err != nil {
-			return nil, err
-		}
-		defer sk.Close()
+func measureAsSPDX(ctx context.Context, isFakeDevice bool, measName, measNote string) (*SPDXResponse, error) {
+	sk, err := skConnect(isFakeDevice)
+	if err != nil {
+		return nil, err
+	}
+	defer sk.Close()
 
-		meas, err = sk.Measure()
-		if err != nil {
-			return nil, err
-		}
+	meas, err := sk.MeasureContext(ctx)
+	if err != nil {
+		return nil, err
 	}
 
 	var response SPDXResponse
 
 	measTime := time.Now()
 
-	response.Header = skreader.NewSPDXHeader(measName, measNote, measTime)
+	response.Header = skreader.NewSPDXHeaderFromMeasurement(meas, measName, measNote, measTime)
 	response.SpectralDistribution = skreader.NewSPDXSpectralDistribution(meas)
 
 	return &response, nil
@@ -493,7 +978,14 @@ func main() {
 		Suggest:                true,
 		EnableBashCompletion:   true,
 		UseShortOptionHandling: true,
+		Before:                 setupGlobals,
+		After:                  closeTraces,
 		Commands: []*cli.Command{
+			{
+				Name:   "list",
+				Usage:  "Lists all connected devices, use --device flag to select one of them",
+				Action: listCmd,
+			},
 			{
 				Name:   "info",
 				Usage:  "Shows info about the connected device",
@@ -504,6 +996,29 @@ func main() {
 				Usage:  "Runs one measurement and outputs selected data as plain text",
 				Action: measureCmd,
 				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "mode",
+						Aliases: []string{"m"},
+						Usage:   "measuring mode: ambient, flash (cordless flash) or cord-flash",
+						Value:   "ambient",
+					},
+					&cli.BoolFlag{
+						Name:    "continuous",
+						Aliases: []string{"C"},
+						Usage:   "measure continuously until ctrl+c (ambient mode only)",
+					},
+					&cli.IntFlag{
+						Name:  "repeat",
+						Usage: "measure `N` times and output mean, standard deviation, min and max (ambient mode only)",
+					},
+					&cli.DurationFlag{
+						Name:  "interval",
+						Usage: "wait this long between repeated measurements",
+					},
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "output statistics of repeated measurements as JSON",
+					},
 					&cli.BoolFlag{
 						Name:    "ldi",
 						Aliases: []string{"l"},
@@ -554,6 +1069,19 @@ func main() {
 						Aliases: []string{"r"},
 						Usage:   "include CRI (Ra, Ri) values",
 					},
+					&cli.BoolFlag{
+						Name:    "tm30",
+						Aliases: []string{"tm"},
+						Usage:   "include TM-30 (Rf, Rg, hue bins) values (C-7000 FW > 25 only)",
+					},
+					&cli.BoolFlag{
+						Name:  "ssi",
+						Usage: "include SSI (tungsten and daylight reference) values (C-7000 FW > 25 only)",
+					},
+					&cli.BoolFlag{
+						Name:  "tlci",
+						Usage: "include TLCI-2012 and TLMF values (C-7000 FW > 25 only)",
+					},
 					&cli.BoolFlag{
 						Name:    "spectra1nm",
 						Aliases: []string{"1mm", "1"},
@@ -628,12 +1156,70 @@ func main() {
 					},
 				},
 			},
+			{
+				Name:   "serve-usb",
+				Usage:  "Exposes the device to --remote clients on other hosts via TCP",
+				Action: serveUsbCmd,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "address",
+						Aliases: []string{"a"},
+						Usage:   "bind address",
+						Value:   "0.0.0.0",
+					},
+					&cli.IntFlag{
+						Name:    "port",
+						Aliases: []string{"p"},
+						Usage:   "bind port",
+						Value:   skreader.NetworkPortDefault,
+					},
+				},
+			},
 		},
 		Flags: []cli.Flag{
 			&cli.BoolFlag{
 				Name:    "fake-device",
 				Aliases: []string{"fake", "f"},
-				Usage:   "use fake device for testing",
+				Usage:   "use simulated device for testing",
+			},
+			&cli.StringFlag{
+				Name:  "device",
+				Usage: "use the device with this serial number or USB port path (e.g. 1-2.3), see list command",
+			},
+			&cli.StringFlag{
+				Name:  "remote",
+				Usage: "use the device served by serve-usb command on another host at `ADDR` (host:port)",
+			},
+			&cli.StringFlag{
+				Name:    "token",
+				Usage:   "shared secret of serve-usb command and its --remote clients",
+				EnvVars: []string{"SKREAD_TOKEN"},
+			},
+			&cli.DurationFlag{
+				Name:  "usb-timeout",
+				Usage: "give up on USB transfer not completed in this time (disconnected or not responding device)",
+				Value: skreader.UsbReadTimeoutDefault,
+			},
+			&cli.DurationFlag{
+				Name:  "measure-timeout",
+				Usage: "give up on measurement not completed in this time (increase for long exposure times)",
+				Value: skreader.WaitMeasTimeoutDefault,
+			},
+			&cli.IntFlag{
+				Name:  "reconnect",
+				Usage: "try to reconnect the device this many times after USB transfer error (webserver defaults to 5)",
+			},
+			&cli.BoolFlag{
+				Name:  "debug",
+				Usage: "print every command sent to the device, its response and every status poll to stderr",
+			},
+			&cli.StringFlag{
+				Name:  "record",
+				Usage: "append all USB traffic to the trace `FILE` (not supported by webserver command)",
+			},
+			&cli.StringFlag{
+				Name:  "replay",
+				Usage: "replay USB traffic from the trace `FILE` recorded with --record instead of using the device",
 			},
 		},
 	}
@@ -644,7 +1230,12 @@ func main() {
 		Usage:   "print only the version",
 	}
 
-	if err := app.Run(os.Args); err != nil {
-		log.Fatal(err)
+	// Cancel running command on ctrl-c. Device is returned to normal control mode by the library.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := app.RunContext(ctx, os.Args); err != nil {
+		stop()
+		log.Fatal(err) //nolint:gocritic
 	}
 }
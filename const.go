@@ -1,5 +1,7 @@
 package skreader
 
+import "fmt"
+
 type SkCommand string
 
 const (
@@ -63,6 +65,24 @@ const (
 	SkMeasuringModeCordFlash
 )
 
+// IsFlash reports whether measuring mode is one of flash measuring modes.
+func (m SkMeasuringMode) IsFlash() bool {
+	return m == SkMeasuringModeCordlessFlash || m == SkMeasuringModeCordFlash
+}
+
+func (m SkMeasuringMode) String() string {
+	switch m {
+	case SkMeasuringModeAmbient:
+		return "ambient"
+	case SkMeasuringModeCordlessFlash:
+		return "cordless-flash"
+	case SkMeasuringModeCordFlash:
+		return "cord-flash"
+	default:
+		return fmt.Sprintf("SkMeasuringMode(%d)", int(m))
+	}
+}
+
 type SkFieldOfView int
 
 const (
@@ -70,6 +90,17 @@ const (
 	SkFieldOfView10Deg                      // 10°
 )
 
+func (f SkFieldOfView) String() string {
+	switch f {
+	case SkFieldOfView2Deg:
+		return "2°"
+	case SkFieldOfView10Deg:
+		return "10°"
+	default:
+		return fmt.Sprintf("SkFieldOfView(%d)", int(f))
+	}
+}
+
 type SkExposureTime int
 
 const (
@@ -78,6 +109,19 @@ const (
 	SkExposureTime1Sec                   // 1 s
 )
 
+func (e SkExposureTime) String() string {
+	switch e {
+	case SkExposureTimeAuto:
+		return "auto"
+	case SkExposureTime100Msec:
+		return "0.1s"
+	case SkExposureTime1Sec:
+		return "1s"
+	default:
+		return fmt.Sprintf("SkExposureTime(%d)", int(e))
+	}
+}
+
 type SkShutterSpeed string
 
 const (
@@ -93,8 +137,12 @@ const (
 	SkShutterSpeed500Sec SkShutterSpeed = "10" // 1/500 s
 )
 
+// SkMeasuringMethod is single or continuous measuring.
+//
+// Deprecated: it is not used by this package, use Device.Measure or Device.MeasureContinuous instead.
 type SkMeasuringMethod int
 
+// Deprecated: see SkMeasuringMethod.
 const (
 	SkMeasuringMethodSingle SkMeasuringMethod = iota
 	SkMeasuringMethodContinuous
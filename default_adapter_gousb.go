@@ -0,0 +1,13 @@
+//go:build cgo && !usbfs
+
+package skreader
+
+// NewDefaultAdapter creates adapter for real device, GousbAdapter in this build.
+func NewDefaultAdapter(opts UsbAdapterOptions) UsbAdapter {
+	return NewGousbAdapter(opts)
+}
+
+// ListDevices returns all connected SEKONIC devices, see ListGousbDevices.
+func ListDevices() ([]UsbDeviceInfo, error) {
+	return ListGousbDevices()
+}
@@ -0,0 +1,30 @@
+//go:build !linux && (!cgo || usbfs)
+
+package skreader
+
+import "errors"
+
+// ErrNoUsbBackend is returned by real device adapter in builds without USB support,
+// i.e. without cgo (libusb) on other OS than Linux. Non-USB adapters can still be used.
+var ErrNoUsbBackend = errors.New("no USB backend in this build, rebuild with cgo enabled to use libusb")
+
+// NewDefaultAdapter creates adapter for real device. There is no USB backend in this build,
+// so returned adapter fails to open with ErrNoUsbBackend.
+func NewDefaultAdapter(_ UsbAdapterOptions) UsbAdapter {
+	return noUsbAdapter{}
+}
+
+// ListDevices returns ErrNoUsbBackend as there is no USB backend in this build.
+func ListDevices() ([]UsbDeviceInfo, error) {
+	return nil, ErrNoUsbBackend
+}
+
+// noUsbAdapter is UsbAdapter of builds without USB backend.
+type noUsbAdapter struct{}
+
+func (noUsbAdapter) Open() error                   { return ErrNoUsbBackend }
+func (noUsbAdapter) Close() error                  { return nil }
+func (noUsbAdapter) Read(_ []byte) (int, error)    { return 0, ErrNoUsbBackend }
+func (noUsbAdapter) Write(_ []byte) (int, error)   { return 0, ErrNoUsbBackend }
+func (noUsbAdapter) Manufacturer() (string, error) { return "", ErrNoUsbBackend }
+func (noUsbAdapter) Product() (string, error)      { return "", ErrNoUsbBackend }
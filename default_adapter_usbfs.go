@@ -0,0 +1,13 @@
+//go:build linux && (!cgo || usbfs)
+
+package skreader
+
+// NewDefaultAdapter creates adapter for real device, UsbfsAdapter in this build.
+func NewDefaultAdapter(opts UsbAdapterOptions) UsbAdapter {
+	return NewUsbfsAdapter(opts)
+}
+
+// ListDevices returns all connected SEKONIC devices, see ListUsbfsDevices.
+func ListDevices() ([]UsbDeviceInfo, error) {
+	return ListUsbfsDevices()
+}
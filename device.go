@@ -2,19 +2,27 @@ package skreader
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
 
 const (
-	WaitConnTimeoutDefault = time.Duration(5) * time.Second       // how long to wait for device to start measuring
-	WaitMeasTimeoutDefault = time.Duration(20) * time.Second      // how long to wait for device to end measuring
-	WaitPollFreqDefault    = time.Duration(50) * time.Millisecond // how often to poll device for status
+	WaitConnTimeoutDefault  = time.Duration(5) * time.Second       // how long to wait for device to start measuring
+	WaitMeasTimeoutDefault  = time.Duration(20) * time.Second      // how long to wait for device to end measuring
+	WaitFlashTimeoutDefault = time.Duration(60) * time.Second      // how long to wait for flash to be fired and measured
+	WaitPollFreqDefault     = time.Duration(50) * time.Millisecond // how often to poll device for status
+	WaitRespTimeoutDefault  = time.Duration(2) * time.Second       // how long to wait for the rest of response split into several transfers
 
-	ReadBufSize = MeasurementDataValidSize // MeasurementDataValidSize bytes is currently the maximum size of the data that can be sent by the device
+	ReconnectAttemptsDefault   = 5                                     // how many times to try to reconnect in resilient mode
+	ReconnectBackoffDefault    = time.Duration(500) * time.Millisecond // delay before the first reconnect attempt
+	ReconnectMaxBackoffDefault = time.Duration(5) * time.Second        // maximal delay between reconnect attempts
+
+	ReadBufSize = MeasurementDataValidSize // minimal read buffer size, it is grown for commands with longer responses
 )
 
 var (
@@ -25,11 +33,21 @@ var (
 type Device struct {
 	adapter UsbAdapter
 
-	Manufacturer string
-	Product      string
+	Manufacturer string // as reported when device was created, see String for the current one
+	Product      string // as reported when device was created, see String for the current one
 
 	MeasurementConfig DeviceMeasurementConfig // Currently supported only by C-7000
 
+	Reconnect ReconnectPolicy // resilient mode settings, disabled by default
+
+	ConfigPolicy ConfigPolicy // what to do with MeasurementConfig settings device can't set remotely, lenient by default
+
+	opts         deviceOptions
+	capabilities DeviceCapabilities
+	connection   int    // incremented every time device is reconnected
+	manufacturer string // current Manufacturer, updated on reconnect
+	product      string // current Product, updated on reconnect
+
 	mu sync.Mutex
 }
 
@@ -43,7 +61,7 @@ type DeviceState struct {
 
 // DeviceMeasurementConfig represents configuration used for measurement.
 type DeviceMeasurementConfig struct {
-	MeasuringMode SkMeasuringMode // only ambient is supported yet
+	MeasuringMode SkMeasuringMode // use Measure for ambient and MeasureFlash for flash modes
 	FieldOfView   SkFieldOfView
 	ExposureTime  SkExposureTime
 	ShutterSpeed  SkShutterSpeed
@@ -51,6 +69,7 @@ type DeviceMeasurementConfig struct {
 
 // NewDeviceWithAdapter creates SEKONIC device handler using provided UsbAdapter concrete implementation.
 // Running this function will ensure that device is connected and will read its minimal USB device info.
+// Device model and firmware version are also requested to detect device capabilities.
 //
 // Sending commands to device is done by calling methods on returned Device struct.
 //
@@ -61,7 +80,17 @@ type DeviceMeasurementConfig struct {
 //
 // After using device, Close method must be called to release all allocated resources.
 // Until Close is called, device may not be available for other processes.
+//
+// It is the same as NewDevice with default options.
 func NewDeviceWithAdapter(adapter UsbAdapter) (*Device, error) {
+	return NewDevice(adapter)
+}
+
+// NewDevice creates SEKONIC device handler using provided UsbAdapter like NewDeviceWithAdapter does,
+// but also applies given options, e.g. timeouts, poll interval and initial measurement configuration:
+//
+//	d, err := NewDevice(adapter, WithMeasureTimeout(time.Minute), WithPollInterval(10*time.Millisecond))
+func NewDevice(adapter UsbAdapter, opts ...DeviceOption) (*Device, error) {
 	if adapter == nil {
 		return nil, fmt.Errorf("adapter is nil")
 	}
@@ -81,33 +110,92 @@ func NewDeviceWithAdapter(adapter UsbAdapter) (*Device, error) {
 		return nil, err
 	}
 
-	// Default measurement configuration with recommended values.
-	defaultConfig := DeviceMeasurementConfig{
-		MeasuringMode: SkMeasuringModeAmbient,
-		FieldOfView:   SkFieldOfView2Deg,
-		ExposureTime:  SkExposureTimeAuto,
-		ShutterSpeed:  SkShutterSpeed125Sec,
-	}
-
-	return &Device{ //nolint:exhaustruct
+	d := &Device{ //nolint:exhaustruct
 		adapter:           adapter,
 		Manufacturer:      manufacturer,
 		Product:           product,
-		MeasurementConfig: defaultConfig,
-	}, nil
+		MeasurementConfig: DefaultMeasurementConfig(),
+		manufacturer:      manufacturer,
+		product:           product,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	err = d.detectCapabilities(context.Background(), d.execCommand)
+	if err != nil {
+		_ = adapter.Close()
+
+		return nil, err
+	}
+	d.logf("%s connected: model %s, firmware %s", d, d.capabilities.Model, d.capabilities.FirmwareInfo)
+
+	return d, nil
+}
+
+// detectCapabilities requests device model and firmware version and sets device capabilities accordingly.
+// Commands are executed with exec, so that it can be used while command lock is held.
+func (d *Device) detectCapabilities(ctx context.Context, exec execFunc) error {
+	model, err := d.modelName(ctx, exec)
+	if err != nil {
+		return fmt.Errorf("could not detect device capabilities: %w", err)
+	}
+
+	fw, err := d.firmwareInfo(ctx, exec)
+	if errors.Is(err, ErrUnexpectedResponse) {
+		// Unknown firmware info format, device is still usable with capabilities of the model
+		// which don't depend on firmware version.
+		d.logf("%s: unknown firmware, using base model capabilities: %v", model, err)
+		d.capabilities = NewDeviceCapabilities(model, 0)
+
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("could not detect device capabilities: %w", err)
+	}
+
+	d.capabilities = NewDeviceCapabilities(model, fw.Main())
+	d.capabilities.FirmwareInfo = *fw
+
+	return nil
+}
+
+// connectionNumber returns number of times device was reconnected.
+func (d *Device) connectionNumber() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.connection
+}
+
+// Capabilities returns device capabilities detected when device was connected (or reconnected).
+// Returned value is a copy, it is not changed when device is reconnected.
+func (d *Device) Capabilities() DeviceCapabilities {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.capabilities.clone()
 }
 
 // String returns device readble name. It tries to use Manufacturer and Product, but if any of them
-// is empty, it uses "SEKONIC" dummy name.
+// is empty, it uses "SEKONIC" dummy name. Names of reconnected device are used after reconnect.
 func (d *Device) String() string {
-	if d.Product != "" && d.Manufacturer != "" {
-		return fmt.Sprintf("%s %s", d.Manufacturer, d.Product)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.name()
+}
+
+// name is String which must be called with command lock held.
+func (d *Device) name() string {
+	if d.product != "" && d.manufacturer != "" {
+		return fmt.Sprintf("%s %s", d.manufacturer, d.product)
 	}
-	if d.Manufacturer != "" {
-		return d.Manufacturer
+	if d.manufacturer != "" {
+		return d.manufacturer
 	}
-	if d.Product != "" {
-		return d.Product
+	if d.product != "" {
+		return d.product
 	}
 
 	return "SEKONIC"
@@ -115,80 +203,289 @@ func (d *Device) String() string {
 
 // Measure performs one measurement and returns result.
 func (d *Device) Measure() (*Measurement, error) {
-	err := d.WaitReady(WaitConnTimeoutDefault, WaitPollFreqDefault)
+	return d.MeasureContext(context.Background())
+}
+
+// MeasureContext performs one measurement and returns result.
+// Measuring is aborted as soon as ctx is done. Device is always switched back
+// to normal (remote off) control mode before returning, even if ctx is done.
+func (d *Device) MeasureContext(ctx context.Context) (*Measurement, error) {
+	if d.MeasurementConfig.MeasuringMode.IsFlash() {
+		return nil, fmt.Errorf("flash measuring mode is configured, use MeasureFlash instead")
+	}
+
+	s, err := d.BeginSessionContext(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer s.Close()
 
-	err = d.SetRemoteOn()
+	return s.MeasureContext(ctx)
+}
+
+// MeasureN performs n measurements with interval between them and returns their statistics,
+// e.g. mean and repeatability of five shots. Device stays in remote control mode the whole time.
+// Error is returned if any of measurements fails.
+func (d *Device) MeasureN(n int, interval time.Duration) (*MeasurementStats, error) {
+	return d.MeasureNContext(context.Background(), n, interval)
+}
+
+// MeasureNContext is like MeasureN but aborts as soon as ctx is done.
+func (d *Device) MeasureNContext(ctx context.Context, n int, interval time.Duration) (*MeasurementStats, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("invalid number of measurements: %d", n)
+	}
+
+	s, err := d.BeginSessionContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer s.Close()
+
+	measurements := make([]*Measurement, 0, n)
+	for i := 0; i < n; i++ {
+		if i > 0 && interval > 0 {
+			select {
+			case <-time.After(interval):
+			case <-ctx.Done():
+				return nil, fmt.Errorf("measuring canceled: %w", ctx.Err())
+			}
+		}
+
+		meas, err := s.MeasureContext(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("measurement %d of %d: %w", i+1, n, err)
+		}
+		measurements = append(measurements, meas)
+	}
+
+	return NewMeasurementStats(measurements), nil
+}
+
+// MeasurementEvent represents one result delivered by MeasureContinuous.
+// Either Measurement or Err is set.
+type MeasurementEvent struct {
+	Measurement *Measurement
+	Err         error
+	Time        time.Time // when the result was read from device
+	Dropped     int       // number of previous events dropped because receiver was not keeping up
+}
+
+// MeasureContinuous performs successive measurements until ctx is done
+// and delivers results to the returned channel. Device stays in remote control mode the whole time
+// and is switched back to normal control mode when ctx is done. Channel is closed after that.
+//
+// Errors of individual measurements are delivered as events and measuring goes on.
+// If receiver is not keeping up, only the latest event is kept and older ones are dropped,
+// number of dropped events is reported in the Dropped field of the next delivered event.
+//
+// Error is returned only if device could not be prepared for measuring.
+func (d *Device) MeasureContinuous(ctx context.Context) (<-chan MeasurementEvent, error) {
+	if d.MeasurementConfig.MeasuringMode.IsFlash() {
+		return nil, fmt.Errorf("flash measuring mode is configured, continuous measuring supports ambient mode only")
+	}
+
+	err := d.WaitReadyContext(ctx, d.connTimeout(), d.pollInterval())
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.SetRemoteOnContext(ctx)
 	if err != nil {
 		return nil, err
 	}
-	defer func() { _ = d.SetRemoteOff() }()
 
-	err = d.SetMeasurementConfiguration()
+	applied, err := d.applyMeasurementConfiguration(ctx)
 	if err != nil {
+		_ = d.SetRemoteOffContext(context.Background())
+
 		return nil, err
 	}
 
-	err = d.StartMeasuring()
+	events := make(chan MeasurementEvent, 1)
+
+	go func() {
+		defer close(events)
+		// Use separate context here, ctx is already done at this moment.
+		defer func() { _ = d.SetRemoteOffContext(context.Background()) }()
+
+		for ctx.Err() == nil {
+			meas, err := d.measureRemote(ctx)
+			if ctx.Err() != nil {
+				return // do not report errors caused by cancellation
+			}
+			if meas != nil {
+				meas.Config = applied
+			}
+			sendLatest(events, MeasurementEvent{
+				Measurement: meas,
+				Err:         err,
+				Time:        time.Now(),
+				Dropped:     0,
+			})
+			if err != nil {
+				// Give device (or user) some time to recover before next attempt.
+				select {
+				case <-time.After(d.pollInterval()):
+				case <-ctx.Done():
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// measureRemote performs one measurement assuming device is already in remote control mode and configured.
+func (d *Device) measureRemote(ctx context.Context) (*Measurement, error) {
+	err := d.StartMeasuringContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	err = d.WaitReady(WaitMeasTimeoutDefault, WaitPollFreqDefault)
+	err = d.WaitReadyContext(ctx, d.measTimeout(), d.pollInterval())
+	if err != nil {
+		return nil, err
+	}
+
+	return d.MeasurementResultContext(ctx)
+}
+
+// sendLatest sends event to buffered channel without blocking. If channel buffer is full,
+// pending event is replaced with the new one. Must be used by the only sender of the channel.
+func sendLatest(ch chan MeasurementEvent, ev MeasurementEvent) {
+	for {
+		select {
+		case ch <- ev:
+			return
+		default:
+		}
+		select {
+		case old := <-ch:
+			ev.Dropped += old.Dropped + 1
+		default:
+		}
+	}
+}
+
+// MeasureFlash performs one flash measurement using configured flash measuring mode and returns result.
+// In cordless flash mode device is armed and waits for the flash to be fired by the user.
+// In cord flash mode device fires the flash connected with sync cord itself.
+func (d *Device) MeasureFlash() (*FlashMeasurement, error) {
+	return d.MeasureFlashContext(context.Background())
+}
+
+// MeasureFlashContext is like MeasureFlash but aborts as soon as ctx is done.
+// Device is always switched back to normal (remote off) control mode before returning, even if ctx is done.
+func (d *Device) MeasureFlashContext(ctx context.Context) (*FlashMeasurement, error) {
+	if !d.MeasurementConfig.MeasuringMode.IsFlash() {
+		return nil, fmt.Errorf("flash measuring mode is not configured, use Measure instead")
+	}
+
+	s, err := d.BeginSessionContext(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer s.Close()
 
-	return d.MeasurementResult()
+	return s.MeasureFlashContext(ctx)
 }
 
 // WaitReady waits for device to be ready for next measurement.
 // It polls device state every step duration until idle status is reached or timeout duration is reached.
-// If device state is not valid for measurement, error is returned.
-// If timeout is reached, error is returned.
+// If device state is not valid for measurement, ErrHardware, ErrRingNotLow or ErrMeasureButtonPressed is returned.
+// If timeout is reached, error wrapping ErrTimeout is returned.
 func (d *Device) WaitReady(duration, step time.Duration) error {
+	return d.WaitReadyContext(context.Background(), duration, step)
+}
+
+// WaitReadyContext is like WaitReady but also stops waiting and returns an error
+// wrapping ctx.Err() as soon as ctx is done.
+func (d *Device) WaitReadyContext(ctx context.Context, duration, step time.Duration) error {
+	start := time.Now()
 	timeout := time.After(duration)
 	ticker := time.NewTicker(step)
 	defer ticker.Stop()
-	for {
+	for iteration := 1; ; iteration++ {
 		select {
 		case <-ticker.C:
-			st, e := d.State()
+			st, e := d.StateContext(ctx)
+			if d.opts.tracer != nil {
+				d.opts.tracer.TracePoll(PollTrace{Iteration: iteration, Elapsed: time.Since(start), State: st, Err: e})
+			}
 			if e != nil {
 				continue // ignore status read error, will repeat in next tick
 			}
+			if st.Status == SkDeviceStatusErrorHw {
+				return ErrHardware
+			}
 			if st.Ring != SkRingStatusLow {
-				return fmt.Errorf("ring is not set to low position")
+				return ErrRingNotLow
 			}
 			if st.Button == SkButtonStatusMeasuring {
-				return fmt.Errorf("measuring button is pressed")
+				return ErrMeasureButtonPressed
 			}
 			if st.Status == SkDeviceStatusIdle ||
 				st.Status == SkDeviceStatusIdleOutMeas {
 				return nil // waiting succeeded
 			}
 		case <-timeout:
-			return fmt.Errorf("timeout waiting for device to end measuring (%s)", duration)
+			return fmt.Errorf("%w waiting for device to end measuring (%s)", ErrTimeout, duration)
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for device canceled: %w", ctx.Err())
 		}
 	}
 }
 
 // MeasurementResult requests device measurement result data.
+// Data is decoded by the decoder DefaultMeasurementDecoders selects for the device model, firmware and data size.
 func (d *Device) MeasurementResult() (*Measurement, error) {
+	return d.MeasurementResultContext(context.Background())
+}
+
+// MeasurementResultContext is like MeasurementResult but uses ctx for the command execution.
+func (d *Device) MeasurementResultContext(ctx context.Context) (*Measurement, error) {
 	// Response data example:
 	// NR@@@ + data
-	data, err := d.execCommand(SkCommandGetMeasurementResult, 0, 0)
+	data, err := d.execCommand(ctx, SkCommandGetMeasurementResult, 0, 0)
 	if err != nil {
 		return nil, err
 	}
 
-	return NewMeasurementFromBytes(data)
+	return DefaultMeasurementDecoders.Decode(d.Capabilities(), data, SkMeasuringModeAmbient)
+}
+
+// FlashMeasurementResult requests device flash measurement result data.
+func (d *Device) FlashMeasurementResult() (*FlashMeasurement, error) {
+	return d.FlashMeasurementResultContext(context.Background())
+}
+
+// FlashMeasurementResultContext is like FlashMeasurementResult but uses ctx for the command execution.
+func (d *Device) FlashMeasurementResultContext(ctx context.Context) (*FlashMeasurement, error) {
+	data, err := d.execCommand(ctx, SkCommandGetMeasurementResult, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := DefaultMeasurementDecoders.Decode(d.Capabilities(), data, d.MeasurementConfig.MeasuringMode)
+	if err != nil {
+		return nil, err
+	}
+
+	return newFlashMeasurement(m), nil
 }
 
 // ModelName requests device model name.
 func (d *Device) ModelName() (string, error) {
+	return d.ModelNameContext(context.Background())
+}
+
+// ModelNameContext is like ModelName but uses ctx for the command execution.
+func (d *Device) ModelNameContext(ctx context.Context) (string, error) {
+	return d.modelName(ctx, d.execCommand)
+}
+
+func (d *Device) modelName(ctx context.Context, exec execFunc) (string, error) {
 	// Response data example (chars):
 	// MN@@@C-800\x00\x00\x00\x00\x00
 	//      ^ Model Name chars start at pos 5, end randomly with bunch of trailing null bytes
@@ -197,7 +494,7 @@ func (d *Device) ModelName() (string, error) {
 		datapos = 5
 		datalen = 0
 	)
-	data, err := d.execCommand(cmd, datapos, datalen) // -> "C-800\x00\x00\x00\x00\x00"
+	data, err := exec(ctx, cmd, datapos, datalen) // -> "C-800\x00\x00\x00\x00\x00"
 	if err != nil {
 		return "", err
 	}
@@ -207,29 +504,58 @@ func (d *Device) ModelName() (string, error) {
 
 // FirmwareVersion requests device main firmware version.
 func (d *Device) FirmwareVersion() (int, error) {
+	return d.FirmwareVersionContext(context.Background())
+}
+
+// FirmwareVersionContext is like FirmwareVersion but uses ctx for the command execution.
+func (d *Device) FirmwareVersionContext(ctx context.Context) (int, error) {
+	info, err := d.FirmwareInfoContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return info.Main(), nil // -> 27
+}
+
+// FirmwareInfo requests versions and checksums of all device firmware components.
+func (d *Device) FirmwareInfo() (*FirmwareInfo, error) {
+	return d.FirmwareInfoContext(context.Background())
+}
+
+// FirmwareInfoContext is like FirmwareInfo but uses ctx for the command execution.
+func (d *Device) FirmwareInfoContext(ctx context.Context) (*FirmwareInfo, error) {
+	return d.firmwareInfo(ctx, d.execCommand)
+}
+
+func (d *Device) firmwareInfo(ctx context.Context, exec execFunc) (*FirmwareInfo, error) {
 	// Response data example (chars):
 	// FV@@@20,C36E,27,7881,11,B216,14,50CC,17,74EC
-	//              27 <- main FW version chars are at pos 13 and 14 (used for feature detection)
-	//                      11 <-   14 <- other FW versions (not used by this pkg)
+	//      ^ version and checksum pairs start at pos 5
+	//              27 <- main FW version (used for feature detection)
 	const (
 		cmd     = SkCommandGetFirmwareVersion
-		datapos = 13
-		datalen = 2
+		datapos = 5
+		datalen = 0
 	)
-	data, err := d.execCommand(cmd, datapos, datalen) // -> "27"
+	data, err := exec(ctx, cmd, datapos, datalen) // -> "20,C36E,27,7881,11,B216,14,50CC,17,74EC"
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
-	ver, err := toInt(data)
+	info, err := parseFirmwareInfo(toString(data))
 	if err != nil {
-		return 0, fmt.Errorf("%s command error: invalid response: %v: %s", cmd, data, err)
+		return nil, &ResponseError{Cmd: cmd, Data: data, Err: ErrUnexpectedResponse}
 	}
 
-	return ver, nil // -> 27
+	return &info, nil
 }
 
 // State requests device current operational mode, knobs and buttons states.
 func (d *Device) State() (*DeviceState, error) {
+	return d.StateContext(context.Background())
+}
+
+// StateContext is like State but uses ctx for the command execution.
+func (d *Device) StateContext(ctx context.Context) (*DeviceState, error) {
 	// Response data example (chars)
 	// ST@@@
 	// Response data example (bytes):
@@ -240,7 +566,7 @@ func (d *Device) State() (*DeviceState, error) {
 		datapos = 2
 		datalen = 3
 	)
-	data, err := d.execCommand(cmd, datapos, datalen) // -> [st1 st2 key]
+	data, err := d.execCommand(ctx, cmd, datapos, datalen) // -> [st1 st2 key]
 	if err != nil {
 		return nil, err
 	}
@@ -292,7 +618,44 @@ func (d *Device) State() (*DeviceState, error) {
 }
 
 // SetMeasurementConfiguration sends measurement configuration options to device.
+// Settings device can't set remotely are handled according to ConfigPolicy.
+// Error wrapping ErrUnsupportedConfig is returned if configuration is not valid for device.
 func (d *Device) SetMeasurementConfiguration() error {
+	return d.SetMeasurementConfigurationContext(context.Background())
+}
+
+// SetMeasurementConfigurationContext is like SetMeasurementConfiguration but uses ctx for the commands execution.
+func (d *Device) SetMeasurementConfigurationContext(ctx context.Context) error {
+	_, err := d.applyMeasurementConfiguration(ctx)
+
+	return err
+}
+
+// applyMeasurementConfiguration validates MeasurementConfig, sends it to device and returns what was applied.
+func (d *Device) applyMeasurementConfiguration(ctx context.Context) (*AppliedMeasurementConfig, error) {
+	caps := d.Capabilities()
+
+	applied, skipped, invalid := caps.checkConfig(d.MeasurementConfig)
+	if len(invalid) > 0 {
+		return nil, caps.configError(invalid)
+	}
+	if len(skipped) > 0 {
+		if d.ConfigPolicy == ConfigStrict {
+			return nil, caps.configError(skipped)
+		}
+		d.logf("measurement configuration partially applied: %s", strings.Join(skipped, ", "))
+	}
+
+	err := d.sendMeasurementConfiguration(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &applied, nil
+}
+
+// sendMeasurementConfiguration sends measurement configuration options supported by device.
+func (d *Device) sendMeasurementConfiguration(ctx context.Context) error {
 	if !d.SupportsMeasurementConfiguration() {
 		return nil
 	}
@@ -300,13 +663,13 @@ func (d *Device) SetMeasurementConfiguration() error {
 	const tag = "set measurement configuration"
 
 	setMeasuringMode := fmt.Sprintf("%s,%d", SkCommandSetMeasuringMode, d.MeasurementConfig.MeasuringMode)
-	if _, err := d.execCommand(SkCommand(setMeasuringMode), 0, 0); err != nil {
-		return fmt.Errorf("%s: set measurement mode error: %s", tag, err)
+	if _, err := d.execCommand(ctx, SkCommand(setMeasuringMode), 0, 0); err != nil {
+		return fmt.Errorf("%s: set measurement mode error: %w", tag, err)
 	}
 
 	setShutterSpeed := fmt.Sprintf("%s,0,%s", SkCommandSetShutterSpeed, d.MeasurementConfig.ShutterSpeed)
-	if _, err := d.execCommand(SkCommand(setShutterSpeed), 0, 0); err != nil {
-		return fmt.Errorf("%s: set shutter speed error: %s", tag, err)
+	if _, err := d.execCommand(ctx, SkCommand(setShutterSpeed), 0, 0); err != nil {
+		return fmt.Errorf("%s: set shutter speed error: %w", tag, err)
 	}
 
 	if !d.SupportsExtendedMeasurementConfiguration() {
@@ -314,13 +677,13 @@ func (d *Device) SetMeasurementConfiguration() error {
 	}
 
 	setFov := fmt.Sprintf("%s,%d", SkCommandSetFov, d.MeasurementConfig.FieldOfView)
-	if _, err := d.execCommand(SkCommand(setFov), 0, 0); err != nil {
-		return fmt.Errorf("%s: set field of view error: %s", tag, err)
+	if _, err := d.execCommand(ctx, SkCommand(setFov), 0, 0); err != nil {
+		return fmt.Errorf("%s: set field of view error: %w", tag, err)
 	}
 
 	setExposureTime := fmt.Sprintf("%s,%d", SkCommandSetExposureTime, d.MeasurementConfig.ExposureTime)
-	if _, err := d.execCommand(SkCommand(setExposureTime), 0, 0); err != nil {
-		return fmt.Errorf("%s: set exposure time error: %s", tag, err)
+	if _, err := d.execCommand(ctx, SkCommand(setExposureTime), 0, 0); err != nil {
+		return fmt.Errorf("%s: set exposure time error: %w", tag, err)
 	}
 
 	return nil
@@ -329,7 +692,12 @@ func (d *Device) SetMeasurementConfiguration() error {
 // SetRemoteOn sets device to remote control mode.
 // In this mode, device is ready to receive remote commands.
 func (d *Device) SetRemoteOn() error {
-	_, err := d.execCommand(SkCommandSetRemoteOn, 0, 0)
+	return d.SetRemoteOnContext(context.Background())
+}
+
+// SetRemoteOnContext is like SetRemoteOn but uses ctx for the command execution.
+func (d *Device) SetRemoteOnContext(ctx context.Context) error {
+	_, err := d.execCommand(ctx, SkCommandSetRemoteOn, 0, 0)
 
 	return err
 }
@@ -337,14 +705,24 @@ func (d *Device) SetRemoteOn() error {
 // SetRemoteOff sets device back to normal control mode.
 // In this mode, device is ready to be used manually.
 func (d *Device) SetRemoteOff() error {
-	_, err := d.execCommand(SkCommandSetRemoteOff, 0, 0)
+	return d.SetRemoteOffContext(context.Background())
+}
+
+// SetRemoteOffContext is like SetRemoteOff but uses ctx for the command execution.
+func (d *Device) SetRemoteOffContext(ctx context.Context) error {
+	_, err := d.execCommand(ctx, SkCommandSetRemoteOff, 0, 0)
 
 	return err
 }
 
 // StartMeasuring sends command to device to start measuring.
 func (d *Device) StartMeasuring() error {
-	_, err := d.execCommand(SkCommandStartMeasuring, 0, 0)
+	return d.StartMeasuringContext(context.Background())
+}
+
+// StartMeasuringContext is like StartMeasuring but uses ctx for the command execution.
+func (d *Device) StartMeasuringContext(ctx context.Context) error {
+	_, err := d.execCommand(ctx, SkCommandStartMeasuring, 0, 0)
 
 	return err
 }
@@ -356,47 +734,83 @@ func (d *Device) Close() error {
 	return d.adapter.Close()
 }
 
+// execFunc executes SkCommand, see execCommand.
+type execFunc func(ctx context.Context, cmd SkCommand, datapos, datalen int) ([]byte, error)
+
 // execCommand sends SkCommand to device and reads response. Parameters datapos and datalen are used to extract
 // only necessary bytes from response buffer. If datalen is 0, whole response data is returned.
-func (d *Device) execCommand(cmd SkCommand, datapos, datalen int) ([]byte, error) {
+//
+// The ctx is checked only before the command is sent. Once the command is sent, both acknowledge and
+// main responses are always read, otherwise unread response would break the next command exchange.
+//
+// If resilient mode is enabled, device is reconnected after transfer error, see ReconnectPolicy.
+func (d *Device) execCommand(ctx context.Context, cmd SkCommand, datapos, datalen int) ([]byte, error) {
 	// Ensure only one command at a time
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
+	data, err := d.exec(ctx, cmd, datapos, datalen)
+	if err != nil && d.Reconnect.Attempts > 0 && errors.Is(err, ErrTransfer) {
+		return d.reconnectAndRetry(ctx, cmd, datapos, datalen, err)
+	}
+
+	return data, err
+}
+
+// exec is like execCommand but without command lock and reconnecting.
+func (d *Device) exec(ctx context.Context, cmd SkCommand, datapos, datalen int) (_ []byte, err error) {
+	if err = ctx.Err(); err != nil {
+		return nil, fmt.Errorf("%s command canceled: %w", cmd, err)
+	}
+
+	var ack, data []byte
+	if d.opts.tracer != nil {
+		start := time.Now()
+		defer func() {
+			d.opts.tracer.TraceCommand(CommandTrace{
+				Cmd:      cmd,
+				Ack:      ack,
+				Response: len(data),
+				Latency:  time.Since(start),
+				Err:      err,
+			})
+		}()
+	}
+
 	cmdbytes := []byte(cmd)
 
 	// Send command to device
-	err := d.write(cmdbytes)
+	err = d.write(cmdbytes)
 	if err != nil {
 		return nil, err
 	}
 
 	// Read acknowledge response
-	data, err := d.read()
+	ack, err = d.read(cmd, len(SkResponseOK))
 	if err != nil {
 		return nil, err
 	}
 
 	// Check acknowledge response is OK
-	if !bytes.Equal(data, SkResponseOK) {
-		return nil, fmt.Errorf("not OK response: %v", data)
+	if !bytes.Equal(ack, SkResponseOK) {
+		return nil, &ResponseError{Cmd: cmd, Data: ack, Err: ErrNAK}
 	}
 
 	// Read main response
-	data, err = d.read()
+	data, err = d.read(cmd, d.responseSize(cmd))
 	if err != nil {
 		return nil, err
 	}
 
 	// Boundaries check
-	if len(data) < datapos+datalen {
-		return nil, fmt.Errorf("%s command error: invalid response length %d (min is %d)", cmd, len(data), datapos+datalen)
+	if len(data) < datapos+datalen || len(data) < 2 {
+		return nil, &ResponseError{Cmd: cmd, Data: data, Err: ErrUnexpectedResponse}
 	}
 
 	// Check response is the current command sent response.
 	// Compare first 2 chars only because in response some commands may vary third char.
 	if !bytes.Equal(data[0:2], cmdbytes[0:2]) {
-		return nil, fmt.Errorf("not command response: %v (%s)", data[0:len(cmdbytes)], string(data[0:len(cmdbytes)]))
+		return nil, &ResponseError{Cmd: cmd, Data: data, Err: ErrUnexpectedResponse}
 	}
 
 	if datalen == 0 {
@@ -406,29 +820,77 @@ func (d *Device) execCommand(cmd SkCommand, datapos, datalen int) ([]byte, error
 	return data[datapos : datapos+datalen], nil // return only requested length of response data
 }
 
-// read reads raw binary data from device.
-func (d *Device) read() (buf []byte, err error) {
-	buf = make([]byte, ReadBufSize)
-
-	n, err := d.adapter.Read(buf)
-	if err != nil {
-		return nil, fmt.Errorf("IN endpoint returned an error: %s", err)
+// responseSize returns expected size of the main response to the command or 0 if it is not known in advance.
+func (d *Device) responseSize(cmd SkCommand) int {
+	if cmd != SkCommandGetMeasurementResult {
+		return 0
 	}
-	if n == 0 {
-		return nil, errors.New("IN endpoint returned 0 bytes")
+	if d.capabilities.ExtendedData {
+		return MeasurementDataExtendedSize
 	}
 
-	return buf[:n], nil
+	return MeasurementDataValidSize
+}
+
+// read reads raw binary data of one command response from device.
+// Long responses may be split into several USB transfers, so reading goes on until at least size bytes
+// are received. If size is 0, response is expected to be sent in one transfer.
+// If the rest of response is not received within WaitRespTimeoutDefault or device stops sending it,
+// error wrapping ErrTruncatedResponse is returned. The deadline is checked between transfers only,
+// stalled transfer is limited by adapter read timeout (see UsbAdapterOptions.ReadTimeout).
+func (d *Device) read(cmd SkCommand, size int) ([]byte, error) {
+	bufSize := ReadBufSize
+	if size > bufSize {
+		bufSize = size
+	}
+	buf := make([]byte, bufSize)
+
+	deadline := time.Now().Add(WaitRespTimeoutDefault)
+	received := 0
+	for {
+		n, err := d.adapter.Read(buf[received:])
+		if err != nil {
+			if received > 0 && errors.Is(err, ErrTimeout) {
+				// Device stopped sending in the middle of response, it is not a connection problem.
+				return nil, &ResponseError{Cmd: cmd, Data: buf[:received], Err: ErrTruncatedResponse}
+			}
+			if received > 0 {
+				msg := fmt.Sprintf("IN endpoint returned an error after %d of %d bytes", received, size)
+
+				return nil, &transferError{msg: msg, err: err}
+			}
+
+			return nil, &transferError{msg: "IN endpoint returned an error", err: err}
+		}
+		if n < 0 || n > len(buf)-received {
+			return nil, fmt.Errorf("%w: IN endpoint returned invalid size %d", ErrTransfer, n)
+		}
+		if n == 0 {
+			if received > 0 {
+				return nil, &ResponseError{Cmd: cmd, Data: buf[:received], Err: ErrTruncatedResponse}
+			}
+
+			return nil, fmt.Errorf("%w: IN endpoint returned 0 bytes", ErrTransfer)
+		}
+		received += n
+
+		if received >= size {
+			return buf[:received], nil
+		}
+		if time.Now().After(deadline) {
+			return nil, &ResponseError{Cmd: cmd, Data: buf[:received], Err: ErrTruncatedResponse}
+		}
+	}
 }
 
 // write sends raw binary data to device.
 func (d *Device) write(buf []byte) error {
 	n, err := d.adapter.Write(buf)
 	if err != nil {
-		return fmt.Errorf("OUT endpoint returned an error: %v", err)
+		return &transferError{msg: "OUT endpoint returned an error", err: err}
 	}
 	if n < len(buf) {
-		return fmt.Errorf("OUT endpoint wrote %d bytes only, which is less than data size %d bytes", n, len(buf))
+		return fmt.Errorf("%w: OUT endpoint wrote %d bytes only, which is less than data size %d bytes", ErrTransfer, n, len(buf))
 	}
 
 	return nil
@@ -436,21 +898,14 @@ func (d *Device) write(buf []byte) error {
 
 // SupportsMeasurementConfiguration reports whether device supports
 // measurement configuration.
-// Tested C-700, C-800, C-7000 models.
 func (d *Device) SupportsMeasurementConfiguration() bool {
-	model, _ := d.ModelName()
-
-	return model == "C-7000" || model == "C-800"
+	return d.Capabilities().MeasurementConfiguration
 }
 
 // SupportsExtendedMeasurementConfiguration reports whether device supports
 // extended measurement configuration.
-// Tested C-700, C-800, C-7000 models.
 func (d *Device) SupportsExtendedMeasurementConfiguration() bool {
-	model, _ := d.ModelName()
-	fw, _ := d.FirmwareVersion()
-
-	return model == "C-7000" && fw > 25
+	return d.Capabilities().ExtendedMeasurementConfiguration
 }
 
 // toString converts byte slice to string, ignoring everything after first null byte.
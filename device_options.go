@@ -0,0 +1,97 @@
+package skreader
+
+import (
+	"log"
+	"time"
+)
+
+// DeviceOption configures Device created by NewDevice.
+type DeviceOption func(*Device)
+
+// deviceOptions holds Device settings set by DeviceOption functions. Zero values mean defaults.
+type deviceOptions struct {
+	connTimeout  time.Duration
+	measTimeout  time.Duration
+	flashTimeout time.Duration
+	pollInterval time.Duration
+	logger       *log.Logger
+	tracer       Tracer
+}
+
+// WithConnectTimeout sets how long to wait for device to become ready before measuring, WaitConnTimeoutDefault by default.
+func WithConnectTimeout(timeout time.Duration) DeviceOption {
+	return func(d *Device) { d.opts.connTimeout = timeout }
+}
+
+// WithMeasureTimeout sets how long to wait for device to end measuring, WaitMeasTimeoutDefault by default.
+// Long exposure times in dim scenes may need more.
+func WithMeasureTimeout(timeout time.Duration) DeviceOption {
+	return func(d *Device) { d.opts.measTimeout = timeout }
+}
+
+// WithFlashTimeout sets how long to wait for flash to be fired and measured, WaitFlashTimeoutDefault by default.
+func WithFlashTimeout(timeout time.Duration) DeviceOption {
+	return func(d *Device) { d.opts.flashTimeout = timeout }
+}
+
+// WithPollInterval sets how often device status is polled while waiting for it, WaitPollFreqDefault by default.
+func WithPollInterval(interval time.Duration) DeviceOption {
+	return func(d *Device) { d.opts.pollInterval = interval }
+}
+
+// WithConfig sets initial MeasurementConfig instead of the recommended default one.
+func WithConfig(config DeviceMeasurementConfig) DeviceOption {
+	return func(d *Device) { d.MeasurementConfig = config }
+}
+
+// WithConfigPolicy sets what to do with MeasurementConfig settings device can't set remotely, see Device.ConfigPolicy.
+func WithConfigPolicy(policy ConfigPolicy) DeviceOption {
+	return func(d *Device) { d.ConfigPolicy = policy }
+}
+
+// WithReconnect enables resilient mode with given policy, see Device.Reconnect.
+func WithReconnect(policy ReconnectPolicy) DeviceOption {
+	return func(d *Device) { d.Reconnect = policy }
+}
+
+// WithLogger sets logger for device connection events, such as detected model and reconnect attempts.
+// Nothing is logged by default.
+func WithLogger(logger *log.Logger) DeviceOption {
+	return func(d *Device) { d.opts.logger = logger }
+}
+
+// WithTracer sets tracer receiving every command sent to device and every status poll.
+// Use NewLogTracer to print them.
+func WithTracer(tracer Tracer) DeviceOption {
+	return func(d *Device) { d.opts.tracer = tracer }
+}
+
+func (d *Device) connTimeout() time.Duration {
+	return durationOrDefault(d.opts.connTimeout, WaitConnTimeoutDefault)
+}
+
+func (d *Device) measTimeout() time.Duration {
+	return durationOrDefault(d.opts.measTimeout, WaitMeasTimeoutDefault)
+}
+
+func (d *Device) flashTimeout() time.Duration {
+	return durationOrDefault(d.opts.flashTimeout, WaitFlashTimeoutDefault)
+}
+
+func (d *Device) pollInterval() time.Duration {
+	return durationOrDefault(d.opts.pollInterval, WaitPollFreqDefault)
+}
+
+func (d *Device) logf(format string, args ...interface{}) {
+	if d.opts.logger != nil {
+		d.opts.logger.Printf(format, args...)
+	}
+}
+
+func durationOrDefault(d, def time.Duration) time.Duration {
+	if d <= 0 {
+		return def
+	}
+
+	return d
+}
@@ -0,0 +1,86 @@
+package skreader_test
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/akares/skreader"
+)
+
+func TestNewDeviceWithConfig(t *testing.T) {
+	config := skreader.DeviceMeasurementConfig{
+		MeasuringMode: skreader.SkMeasuringModeAmbient,
+		FieldOfView:   skreader.SkFieldOfView10Deg,
+		ExposureTime:  skreader.SkExposureTimeAuto,
+		ShutterSpeed:  skreader.SkShutterSpeed125Sec,
+	}
+
+	d, err := skreader.NewDevice(skreader.NewSimulatedDevice("C-7000"), skreader.WithConfig(config))
+	if err != nil {
+		t.Fatalf("NewDevice() error = %v", err)
+	}
+	defer d.Close()
+
+	assert.Equal(t, config, d.MeasurementConfig, "MeasurementConfig invalid")
+}
+
+func TestNewDeviceWithMeasureTimeout(t *testing.T) {
+	sim := skreader.NewSimulatedDevice("C-7000")
+	sim.MeasuringDuration = 200 * time.Millisecond
+
+	d, err := skreader.NewDevice(sim,
+		skreader.WithMeasureTimeout(50*time.Millisecond),
+		skreader.WithPollInterval(5*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("NewDevice() error = %v", err)
+	}
+	defer d.Close()
+
+	_, err = d.Measure()
+	if !errors.Is(err, skreader.ErrTimeout) {
+		t.Errorf("Measure() error = %v, want %v", err, skreader.ErrTimeout)
+	}
+
+	d, err = skreader.NewDevice(sim, skreader.WithMeasureTimeout(5*time.Second))
+	if err != nil {
+		t.Fatalf("NewDevice() error = %v", err)
+	}
+	defer d.Close()
+
+	if _, err = d.Measure(); err != nil {
+		t.Errorf("Measure() error = %v", err)
+	}
+}
+
+func TestNewDeviceWithLogger(t *testing.T) {
+	var buf bytes.Buffer
+
+	sim := skreader.NewSimulatedDevice("C-7000")
+	d, err := skreader.NewDevice(sim,
+		skreader.WithLogger(log.New(&buf, "", 0)),
+		skreader.WithReconnect(skreader.ReconnectPolicy{Attempts: 1, Backoff: time.Millisecond, MaxBackoff: 0}),
+	)
+	if err != nil {
+		t.Fatalf("NewDevice() error = %v", err)
+	}
+	defer d.Close()
+
+	sim.Faults.Disconnects = 1
+	if _, err = d.State(); err != nil {
+		t.Fatalf("State() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if assert.Len(t, lines, 3, "log lines invalid") {
+		assert.Contains(t, lines[0], "connected: model C-7000", "connect log invalid")
+		assert.Contains(t, lines[1], "ST command failed, reconnecting", "transfer error log invalid")
+		assert.Contains(t, lines[2], "reconnected: model C-7000", "reconnect log invalid")
+	}
+}
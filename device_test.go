@@ -1,14 +1,45 @@
 package skreader_test
 
 import (
+	"context"
 	"errors"
 	"testing"
+	"time"
 
 	sekonic "github.com/akares/skreader"
 )
 
 var testSKResponseOK = []byte{6, 48}
 
+// newTestDevice connects to the fake adapter answering capabilities detection commands
+// with given model name before the adapter's own responses.
+func newTestDevice(adapter sekonic.UsbAdapter, model string) (*sekonic.Device, error) {
+	var fake *sekonic.FakeusbAdapter
+	switch a := adapter.(type) {
+	case *sekonic.FakeusbAdapter:
+		fake = a
+	case *cancelingAdapter:
+		fake = a.FakeusbAdapter
+	default:
+		return sekonic.NewDeviceWithAdapter(adapter)
+	}
+
+	detection := []sekonic.FakeusbAdapterReadResponse{
+		{Data: testSKResponseOK, Err: nil},
+		{Data: []byte("MN@@@" + model + "\x00"), Err: nil},
+		{Data: testSKResponseOK, Err: nil},
+		{Data: []byte("FV@@@20,C36E,27,7881,11,B216,14,50CC,17,74EC"), Err: nil},
+	}
+	fake.ReadResponse = append(detection, fake.ReadResponse...)
+
+	// Write error is set up for commands sent after connecting.
+	writeErr := fake.WriteResponse
+	fake.WriteResponse = nil
+	defer func() { fake.WriteResponse = writeErr }()
+
+	return sekonic.NewDeviceWithAdapter(adapter)
+}
+
 func TestNewDeviceWithAdapter(t *testing.T) {
 	//nolint:exhaustruct
 	for _, tt := range []struct {
@@ -53,7 +84,7 @@ func TestNewDeviceWithAdapter(t *testing.T) {
 		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
-			_, err := sekonic.NewDeviceWithAdapter(tt.adapter)
+			_, err := newTestDevice(tt.adapter, "C-700")
 			if (err != nil) != tt.wantErr {
 				t.Errorf("NewDeviceWithAdapter() error = %v, wantErr %v", err, tt.wantErr)
 
@@ -63,6 +94,61 @@ func TestNewDeviceWithAdapter(t *testing.T) {
 	}
 }
 
+func TestNewDeviceWithAdapterDetectionError(t *testing.T) {
+	adapter := &sekonic.FakeusbAdapter{ //nolint:exhaustruct
+		ReadResponse: []sekonic.FakeusbAdapterReadResponse{
+			{Data: testSKResponseOK, Err: nil},
+			{Data: []byte("MN@@@C-800\x00"), Err: nil},
+			{Data: nil, Err: errors.New("read error")},
+		},
+	}
+
+	if _, err := sekonic.NewDeviceWithAdapter(adapter); !errors.Is(err, sekonic.ErrTransfer) {
+		t.Errorf("NewDeviceWithAdapter() error = %v, want %v", err, sekonic.ErrTransfer)
+	}
+}
+
+func TestNewDeviceWithAdapterUnknownFirmware(t *testing.T) {
+	for _, tt := range []struct {
+		model      string
+		fw         string
+		wantConfig bool // known models keep capabilities which don't depend on firmware version
+	}{
+		{model: "C-7000", fw: "20,C36E", wantConfig: true},
+		{model: "C-7000", fw: "20,C36E,XX,7881,11", wantConfig: true},
+		{model: "C-7000", fw: "", wantConfig: true},
+		{model: "C-800", fw: "garbage", wantConfig: true},
+		{model: "C-700", fw: "garbage", wantConfig: false},
+	} {
+		t.Run(tt.model+" "+tt.fw, func(t *testing.T) {
+			sim := sekonic.NewSimulatedDevice(tt.model)
+			sim.Firmware = tt.fw
+			sim.MeasuringDuration = 0
+
+			d, err := sekonic.NewDeviceWithAdapter(sim)
+			if err != nil {
+				t.Fatalf("NewDeviceWithAdapter() error = %v", err)
+			}
+			defer d.Close()
+
+			caps := d.Capabilities()
+			if caps.Model != tt.model || caps.Firmware != 0 || caps.ExtendedMeasurementConfiguration || caps.ExtendedData {
+				t.Errorf("Capabilities() = %+v, want %s capabilities without firmware dependent ones", caps, tt.model)
+			}
+			if caps.MeasurementConfiguration != tt.wantConfig {
+				t.Errorf("MeasurementConfiguration = %v, want %v", caps.MeasurementConfiguration, tt.wantConfig)
+			}
+			if got := caps.SupportsMeasuringMode(sekonic.SkMeasuringModeCordlessFlash); got != tt.wantConfig {
+				t.Errorf("SupportsMeasuringMode(cordless flash) = %v, want %v", got, tt.wantConfig)
+			}
+
+			if _, err = d.Measure(); err != nil {
+				t.Errorf("Measure() error = %v", err)
+			}
+		})
+	}
+}
+
 func TestString(t *testing.T) {
 	//nolint:exhaustruct
 	for _, tt := range []struct {
@@ -107,7 +193,7 @@ func TestString(t *testing.T) {
 		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
-			d, _ := sekonic.NewDeviceWithAdapter(tt.adapter)
+			d, _ := newTestDevice(tt.adapter, "C-700")
 
 			if got := d.String(); got != tt.want {
 				t.Errorf("String() = %v, want %v", got, tt.want)
@@ -139,7 +225,7 @@ func TestClose(t *testing.T) {
 		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
-			d, _ := sekonic.NewDeviceWithAdapter(tt.adapter)
+			d, _ := newTestDevice(tt.adapter, "C-700")
 
 			if err := d.Close(); (err != nil) != tt.wantErr {
 				t.Errorf("Close() error = %v, wantErr %v", err, tt.wantErr)
@@ -308,7 +394,7 @@ func TestModelName(t *testing.T) { //nolint:funlen
 		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
-			d, _ := sekonic.NewDeviceWithAdapter(tt.adapter)
+			d, _ := newTestDevice(tt.adapter, "C-700")
 
 			if got, err := d.ModelName(); (err != nil) != tt.wantErr {
 				t.Errorf("ModelName() = %s, want %v, error = %v, wantErr %v", got, tt.want, err, tt.wantErr)
@@ -477,7 +563,7 @@ func TestFirmwareVersion(t *testing.T) { //nolint:funlen
 		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
-			d, _ := sekonic.NewDeviceWithAdapter(tt.adapter)
+			d, _ := newTestDevice(tt.adapter, "C-700")
 
 			if got, err := d.FirmwareVersion(); (err != nil) != tt.wantErr {
 				t.Errorf("FirmwareVersion() = %d, want %d, error = %v, wantErr %v", got, tt.want, err, tt.wantErr)
@@ -485,3 +571,433 @@ func TestFirmwareVersion(t *testing.T) { //nolint:funlen
 		})
 	}
 }
+
+func TestFirmwareInfo(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		data     string
+		wantMain int
+		wantLen  int
+		wantErr  bool
+	}{
+		{
+			name:     "all components",
+			data:     "FV@@@20,C36E,27,7881,11,B216,14,50CC,17,74EC\x00\x00",
+			wantMain: 27,
+			wantLen:  5,
+			wantErr:  false,
+		},
+		{
+			name:     "main component only",
+			data:     "FV@@@20,C36E,27,7881",
+			wantMain: 27,
+			wantLen:  2,
+			wantErr:  false,
+		},
+		{
+			name:     "missing checksum",
+			data:     "FV@@@20,C36E,27",
+			wantMain: 27,
+			wantLen:  1,
+			wantErr:  false,
+		},
+		{
+			name:     "odd number of fields",
+			data:     "FV@@@20,C36E,27,7881,11",
+			wantMain: 27,
+			wantLen:  2,
+			wantErr:  false,
+		},
+		{
+			name:     "invalid version",
+			data:     "FV@@@20,C36E,27,7881,XX,B216",
+			wantMain: 27,
+			wantLen:  2,
+			wantErr:  false,
+		},
+		{
+			name:     "unknown format",
+			data:     "FV@@@20;C36E;27;7881",
+			wantMain: 27,
+			wantLen:  0,
+			wantErr:  false,
+		},
+		{
+			name:    "truncated",
+			data:    "FV@@@20,C36E",
+			wantErr: true,
+		},
+		{
+			name:    "invalid main version",
+			data:    "FV@@@20,C36E,XX,7881",
+			wantErr: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			d, _ := newTestDevice(&sekonic.FakeusbAdapter{ //nolint:exhaustruct
+				ReadResponse: []sekonic.FakeusbAdapterReadResponse{
+					{Data: testSKResponseOK, Err: nil},
+					{Data: []byte(tt.data), Err: nil},
+				},
+			}, "C-7000")
+
+			info, err := d.FirmwareInfo()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FirmwareInfo() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if info.Main() != tt.wantMain || len(info.Components) != tt.wantLen {
+				t.Errorf("FirmwareInfo() = %v, want main %d and %d components", info, tt.wantMain, tt.wantLen)
+			}
+			if want := d.Capabilities().FirmwareInfo.String(); tt.wantLen == 5 && info.String() != want {
+				t.Errorf("FirmwareInfo().String() = %s, want %s", info, want)
+			}
+		})
+	}
+}
+
+// cancelingAdapter records written commands and cancels the context once the given command is written
+// (skipping first skipCancel writes of it).
+type cancelingAdapter struct {
+	*sekonic.FakeusbAdapter
+	cancelOn   string
+	skipCancel int
+	cancel     func()
+	written    []string
+}
+
+func (a *cancelingAdapter) Write(buf []byte) (int, error) {
+	a.written = append(a.written, string(buf))
+	if string(buf) == a.cancelOn {
+		if a.skipCancel == 0 {
+			a.cancel()
+		}
+		a.skipCancel--
+	}
+
+	return len(buf), a.WriteResponse
+}
+
+func TestWaitReadyContextCanceled(t *testing.T) {
+	d, _ := newTestDevice(&sekonic.FakeusbAdapter{}, "C-700") //nolint:exhaustruct
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := d.WaitReadyContext(ctx, time.Second, time.Second)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("WaitReadyContext() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestMeasureContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	adapter := &cancelingAdapter{
+		FakeusbAdapter: &sekonic.FakeusbAdapter{ //nolint:exhaustruct
+			ReadResponse: []sekonic.FakeusbAdapterReadResponse{
+				{Data: testSKResponseOK}, {Data: []byte("ST@@@")}, // WaitReady
+				{Data: testSKResponseOK}, {Data: []byte("RT")}, // SetRemoteOn
+				{Data: testSKResponseOK}, {Data: []byte("RM")}, // StartMeasuring
+				{Data: testSKResponseOK}, {Data: []byte("RT")}, // SetRemoteOff
+			},
+		},
+		cancelOn:   string(sekonic.SkCommandStartMeasuring),
+		skipCancel: 0,
+		cancel:     cancel,
+		written:    nil,
+	}
+
+	d, _ := newTestDevice(adapter, "C-700")
+
+	_, err := d.MeasureContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("MeasureContext() error = %v, want %v", err, context.Canceled)
+	}
+
+	last := adapter.written[len(adapter.written)-1]
+	if last != string(sekonic.SkCommandSetRemoteOff) {
+		t.Errorf("MeasureContext() last command = %s, want %s", last, sekonic.SkCommandSetRemoteOff)
+	}
+}
+
+func TestMeasuringModeMismatch(t *testing.T) {
+	d, _ := newTestDevice(&sekonic.FakeusbAdapter{}, "C-800") //nolint:exhaustruct
+
+	d.MeasurementConfig.MeasuringMode = sekonic.SkMeasuringModeCordlessFlash
+	if _, err := d.Measure(); err == nil {
+		t.Errorf("Measure() in flash mode error = %v, wantErr %v", err, true)
+	}
+
+	d.MeasurementConfig.MeasuringMode = sekonic.SkMeasuringModeAmbient
+	if _, err := d.MeasureFlash(); err == nil {
+		t.Errorf("MeasureFlash() in ambient mode error = %v, wantErr %v", err, true)
+	}
+}
+
+func TestMeasureFlash(t *testing.T) {
+	var responses []sekonic.FakeusbAdapterReadResponse
+	for _, data := range []string{
+		"ST@@@",               // WaitReady
+		"RT",                  // SetRemoteOn
+		"MM",                  // SetMeasurementConfiguration
+		"SS",                  //
+		"RM",                  // StartMeasuring
+		"ST" + "\x41\x50\x40", // WaitReady: flash standby
+		"ST@@@",               // WaitReady: idle
+		string(sekonic.Testdata),
+		"RT", // SetRemoteOff
+	} {
+		responses = append(responses,
+			sekonic.FakeusbAdapterReadResponse{Data: testSKResponseOK, Err: nil},
+			sekonic.FakeusbAdapterReadResponse{Data: []byte(data), Err: nil},
+		)
+	}
+
+	adapter := &cancelingAdapter{
+		FakeusbAdapter: &sekonic.FakeusbAdapter{ //nolint:exhaustruct
+			ReadResponse: responses,
+		},
+		cancelOn:   "",
+		skipCancel: 0,
+		cancel:     nil,
+		written:    nil,
+	}
+
+	d, _ := newTestDevice(adapter, "C-800")
+	d.MeasurementConfig.MeasuringMode = sekonic.SkMeasuringModeCordlessFlash
+
+	f, err := d.MeasureFlash()
+	if err != nil {
+		t.Fatalf("MeasureFlash() error = %v", err)
+	}
+	if f.Illuminance.LuxSecond.Range != sekonic.RangeOk {
+		t.Errorf("MeasureFlash() LuxSecond = %v, want range %v", f.Illuminance.LuxSecond, sekonic.RangeOk)
+	}
+	if adapter.written[4] != "MMw,1" {
+		t.Errorf("MeasureFlash() measuring mode command = %s, want %s", adapter.written[4], "MMw,1")
+	}
+}
+
+func TestMeasureContinuous(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var responses []sekonic.FakeusbAdapterReadResponse
+	for _, data := range []string{
+		"ST@@@",                                 // WaitReady
+		"RT",                                    // SetRemoteOn
+		"RM", "ST@@@", string(sekonic.Testdata), // 1st measurement
+		"RM", "ST@@@", string(sekonic.Testdata), // 2nd measurement
+		"RM", // 3rd measurement is canceled
+		"RT", // SetRemoteOff
+	} {
+		responses = append(responses,
+			sekonic.FakeusbAdapterReadResponse{Data: testSKResponseOK, Err: nil},
+			sekonic.FakeusbAdapterReadResponse{Data: []byte(data), Err: nil},
+		)
+	}
+
+	adapter := &cancelingAdapter{
+		FakeusbAdapter: &sekonic.FakeusbAdapter{ //nolint:exhaustruct
+			ReadResponse: responses,
+		},
+		cancelOn:   string(sekonic.SkCommandStartMeasuring),
+		skipCancel: 2,
+		cancel:     cancel,
+		written:    nil,
+	}
+
+	d, _ := newTestDevice(adapter, "C-700")
+
+	events, err := d.MeasureContinuous(ctx)
+	if err != nil {
+		t.Fatalf("MeasureContinuous() error = %v", err)
+	}
+
+	measured := 0
+	for ev := range events {
+		if ev.Err != nil {
+			t.Errorf("MeasureContinuous() event error = %v", ev.Err)
+		}
+		measured += 1 + ev.Dropped
+	}
+
+	if measured != 2 {
+		t.Errorf("MeasureContinuous() measured = %d, want %d", measured, 2)
+	}
+
+	last := adapter.written[len(adapter.written)-1]
+	if last != string(sekonic.SkCommandSetRemoteOff) {
+		t.Errorf("MeasureContinuous() last command = %s, want %s", last, sekonic.SkCommandSetRemoteOff)
+	}
+}
+
+func TestCommandErrors(t *testing.T) {
+	readErr := errors.New("read error")
+
+	for _, tt := range []struct {
+		name     string
+		response []sekonic.FakeusbAdapterReadResponse
+		want     error
+	}{
+		{
+			name:     "NAK",
+			response: []sekonic.FakeusbAdapterReadResponse{{Data: []byte{21, 48}, Err: nil}},
+			want:     sekonic.ErrNAK,
+		},
+		{
+			name: "unexpected response",
+			response: []sekonic.FakeusbAdapterReadResponse{
+				{Data: testSKResponseOK, Err: nil},
+				{Data: []byte("XX@@@C-800"), Err: nil},
+			},
+			want: sekonic.ErrUnexpectedResponse,
+		},
+		{
+			name:     "transfer error",
+			response: []sekonic.FakeusbAdapterReadResponse{{Data: nil, Err: readErr}},
+			want:     sekonic.ErrTransfer,
+		},
+		{
+			name:     "transfer error keeps adapter error",
+			response: []sekonic.FakeusbAdapterReadResponse{{Data: nil, Err: readErr}},
+			want:     readErr,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			d, _ := newTestDevice(&sekonic.FakeusbAdapter{ //nolint:exhaustruct
+				ReadResponse: tt.response,
+			}, "C-700")
+
+			_, err := d.ModelName()
+			if !errors.Is(err, tt.want) {
+				t.Errorf("ModelName() error = %v, want %v", err, tt.want)
+			}
+
+			var respErr *sekonic.ResponseError
+			if errors.As(err, &respErr) && respErr.Cmd != sekonic.SkCommandGetModelNumber {
+				t.Errorf("ModelName() error command = %s, want %s", respErr.Cmd, sekonic.SkCommandGetModelNumber)
+			}
+		})
+	}
+}
+
+func TestWaitReadyErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		adapter *sekonic.FakeusbAdapter
+		want    error
+	}{
+		{
+			name: "ring not low",
+			adapter: &sekonic.FakeusbAdapter{ //nolint:exhaustruct
+				ReadResponse: []sekonic.FakeusbAdapterReadResponse{
+					{Data: testSKResponseOK, Err: nil},
+					{Data: []byte("ST@@`"), Err: nil},
+				},
+			},
+			want: sekonic.ErrRingNotLow,
+		},
+		{
+			name: "measuring button pressed",
+			adapter: &sekonic.FakeusbAdapter{ //nolint:exhaustruct
+				ReadResponse: []sekonic.FakeusbAdapterReadResponse{
+					{Data: testSKResponseOK, Err: nil},
+					{Data: []byte("ST@@B"), Err: nil},
+				},
+			},
+			want: sekonic.ErrMeasureButtonPressed,
+		},
+		{
+			name: "hardware error",
+			adapter: &sekonic.FakeusbAdapter{ //nolint:exhaustruct
+				ReadResponse: []sekonic.FakeusbAdapterReadResponse{
+					{Data: testSKResponseOK, Err: nil},
+					{Data: []byte("STP@@"), Err: nil},
+				},
+			},
+			want: sekonic.ErrHardware,
+		},
+		{
+			name: "timeout",
+			adapter: &sekonic.FakeusbAdapter{ //nolint:exhaustruct
+				WriteResponse: errors.New("write error"),
+			},
+			want: sekonic.ErrTimeout,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			d, _ := newTestDevice(tt.adapter, "C-700")
+
+			err := d.WaitReady(100*time.Millisecond, 10*time.Millisecond)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("WaitReady() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMeasurementResultSplitResponse(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		model    string
+		data     []byte
+		wantTM30 bool
+	}{
+		{name: "C-7000", model: "C-7000", data: testdataExtended(sekonic.Testdata, 88), wantTM30: true},
+		{name: "C-800", model: "C-800", data: sekonic.Testdata, wantTM30: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := sekonic.NewSimulatedDevice(tt.model)
+			sim.MeasuringDuration = 0
+			sim.MaxTransferSize = 512
+			sim.MeasurementData = tt.data
+
+			d, err := sekonic.NewDeviceWithAdapter(sim)
+			if err != nil {
+				t.Fatalf("NewDeviceWithAdapter() error = %v", err)
+			}
+			defer d.Close()
+
+			m, err := d.Measure()
+			if err != nil {
+				t.Fatalf("Measure() error = %v", err)
+			}
+			if (m.TM30 != nil) != tt.wantTM30 {
+				t.Errorf("Measure() TM30 = %v, want TM30 %v", m.TM30, tt.wantTM30)
+			}
+			if tt.wantTM30 && m.TM30.SampleRf[98].Val != 88 {
+				t.Errorf("Measure() TM30 = %+v, want all values 88", m.TM30)
+			}
+
+			// Next command exchange is not affected by the long response.
+			if _, err = d.State(); err != nil {
+				t.Errorf("State() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestMeasurementResultTruncatedResponse(t *testing.T) {
+	d, _ := newTestDevice(&sekonic.FakeusbAdapter{ //nolint:exhaustruct
+		ReadResponse: []sekonic.FakeusbAdapterReadResponse{
+			{Data: testSKResponseOK, Err: nil},
+			{Data: sekonic.Testdata, Err: nil}, // extended data is expected from C-7000
+			{Data: nil, Err: nil},
+		},
+	}, "C-7000")
+
+	_, err := d.MeasurementResult()
+	if !errors.Is(err, sekonic.ErrTruncatedResponse) {
+		t.Fatalf("MeasurementResult() error = %v, want %v", err, sekonic.ErrTruncatedResponse)
+	}
+
+	var respErr *sekonic.ResponseError
+	if !errors.As(err, &respErr) || len(respErr.Data) != len(sekonic.Testdata) {
+		t.Errorf("MeasurementResult() error = %#v, want ResponseError with received data", err)
+	}
+}
@@ -0,0 +1,136 @@
+package skreader
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	WatchIntervalDefault = time.Duration(1) * time.Second // how often DeviceWatcher checks connected devices
+
+	watchEventsBufSize = 16
+)
+
+// DeviceEventType represents type of DeviceEvent.
+type DeviceEventType int
+
+const (
+	DeviceAttached DeviceEventType = iota
+	DeviceDetached
+)
+
+func (t DeviceEventType) String() string {
+	switch t {
+	case DeviceAttached:
+		return "attached"
+	case DeviceDetached:
+		return "detached"
+	default:
+		return "unknown"
+	}
+}
+
+// DeviceEvent is delivered by DeviceWatcher when device is connected or disconnected.
+type DeviceEvent struct {
+	Type   DeviceEventType
+	Device UsbDeviceInfo
+	Time   time.Time
+}
+
+// DeviceWatcher watches SEKONIC devices being connected and disconnected.
+// Hotplug notifications are not available on all platforms, so connected devices are polled.
+// Replugged device is reported as detached and attached again, because it gets new USB address.
+//
+// Zero value is ready to use and watches devices found by ListDevices every WatchIntervalDefault.
+type DeviceWatcher struct {
+	Interval time.Duration                   // how often to check connected devices, WatchIntervalDefault is used if 0
+	List     func() ([]UsbDeviceInfo, error) // lists connected devices, ListDevices is used if nil
+
+	mu      sync.Mutex
+	devices []UsbDeviceInfo
+}
+
+// Watch starts watching devices until ctx is done and delivers events to the returned channel.
+// Devices already connected are reported as attached first. Channel is closed when ctx is done.
+// Errors of listing devices are ignored, devices are listed again on next check.
+// Watch must be called only once.
+func (w *DeviceWatcher) Watch(ctx context.Context) <-chan DeviceEvent {
+	interval := w.Interval
+	if interval <= 0 {
+		interval = WatchIntervalDefault
+	}
+	list := w.List
+	if list == nil {
+		list = ListDevices
+	}
+
+	events := make(chan DeviceEvent, watchEventsBufSize)
+
+	go func() {
+		defer close(events)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if devs, err := list(); err == nil {
+				w.update(ctx, events, devs)
+			}
+
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events
+}
+
+// Devices returns devices connected at the moment of the last check.
+func (w *DeviceWatcher) Devices() []UsbDeviceInfo {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return append([]UsbDeviceInfo(nil), w.devices...)
+}
+
+// update stores currently connected devices and sends events for the changes since the last check.
+func (w *DeviceWatcher) update(ctx context.Context, events chan<- DeviceEvent, devs []UsbDeviceInfo) {
+	w.mu.Lock()
+	prev := w.devices
+	w.devices = devs
+	w.mu.Unlock()
+
+	now := time.Now()
+	send := func(typ DeviceEventType, dev UsbDeviceInfo) {
+		select {
+		case events <- DeviceEvent{Type: typ, Device: dev, Time: now}:
+		case <-ctx.Done():
+		}
+	}
+
+	for _, dev := range prev {
+		if !containsDevice(devs, dev) {
+			send(DeviceDetached, dev)
+		}
+	}
+	for _, dev := range devs {
+		if !containsDevice(prev, dev) {
+			send(DeviceAttached, dev)
+		}
+	}
+}
+
+// containsDevice reports whether devs contain dev. Devices are identified by bus and address.
+func containsDevice(devs []UsbDeviceInfo, dev UsbDeviceInfo) bool {
+	for _, d := range devs {
+		if d.Bus == dev.Bus && d.Address == dev.Address {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,77 @@
+package skreader_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/akares/skreader"
+)
+
+func TestDeviceWatcher(t *testing.T) {
+	sk1 := skreader.UsbDeviceInfo{Bus: 1, Address: 5, Path: "1-2", Serial: "SN0001", Manufacturer: "SEKONIC", Product: "C-7000"}
+	sk2 := skreader.UsbDeviceInfo{Bus: 1, Address: 6, Path: "1-3", Serial: "SN0002", Manufacturer: "SEKONIC", Product: "C-800"}
+	sk1Replugged := sk1
+	sk1Replugged.Address = 7
+
+	// Connected devices on every check, nil stands for listing error.
+	checks := [][]skreader.UsbDeviceInfo{
+		{sk1},
+		{sk1, sk2},
+		nil,
+		{sk2},
+		{sk2, sk1Replugged},
+	}
+	var mu sync.Mutex
+	list := func() ([]skreader.UsbDeviceInfo, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if len(checks) == 0 {
+			return []skreader.UsbDeviceInfo{sk2, sk1Replugged}, nil
+		}
+		devs := checks[0]
+		checks = checks[1:]
+		if devs == nil {
+			return nil, errors.New("listing failed")
+		}
+
+		return devs, nil
+	}
+
+	w := &skreader.DeviceWatcher{Interval: time.Millisecond, List: list} //nolint:exhaustruct
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := w.Watch(ctx)
+
+	want := []struct {
+		typ skreader.DeviceEventType
+		dev skreader.UsbDeviceInfo
+	}{
+		{skreader.DeviceAttached, sk1},
+		{skreader.DeviceAttached, sk2},
+		{skreader.DeviceDetached, sk1},
+		{skreader.DeviceAttached, sk1Replugged},
+	}
+	for i, exp := range want {
+		select {
+		case ev := <-events:
+			if ev.Type != exp.typ || ev.Device != exp.dev {
+				t.Errorf("event %d = %v %+v, want %v %+v", i, ev.Type, ev.Device, exp.typ, exp.dev)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("event %d not received", i)
+		}
+	}
+
+	if got := w.Devices(); len(got) != 2 || got[0] != sk2 || got[1] != sk1Replugged {
+		t.Errorf("Devices() = %+v, want %+v", got, []skreader.UsbDeviceInfo{sk2, sk1Replugged})
+	}
+
+	// Channel is closed when watching is canceled.
+	cancel()
+	for range events {
+	}
+}
@@ -0,0 +1,104 @@
+package skreader
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned by Device methods. Use errors.Is to check for them, e.g. to ask the operator
+// to turn the ring to low position and retry instead of failing.
+var (
+	ErrRingNotLow               = errors.New("ring is not set to low position")
+	ErrMeasureButtonPressed     = errors.New("measuring button is pressed")
+	ErrHardware                 = errors.New("device hardware error")
+	ErrTimeout                  = errors.New("timeout")
+	ErrNAK                      = errors.New("not OK response")
+	ErrUnexpectedResponse       = errors.New("unexpected response")
+	ErrTransfer                 = errors.New("USB transfer error")
+	ErrUnknownMeasurementLayout = errors.New("unknown measurement data layout")
+	ErrTruncatedResponse        = errors.New("truncated response")
+)
+
+// ResponseError is returned when device responds to the command with NAK, with unexpected data
+// or when only part of the response is received. It wraps ErrNAK, ErrUnexpectedResponse or ErrTruncatedResponse.
+// Use errors.As to get the command and raw response bytes.
+type ResponseError struct {
+	Cmd  SkCommand
+	Data []byte // raw response data
+	Err  error  // ErrNAK, ErrUnexpectedResponse or ErrTruncatedResponse
+}
+
+func (e *ResponseError) Error() string {
+	const maxShown = 16 // measurement result data is too long to be shown whole
+
+	data := e.Data
+	suffix := ""
+	if len(data) > maxShown {
+		data = data[:maxShown]
+		suffix = "..."
+	}
+
+	return fmt.Sprintf("%s command error: %s (%d bytes): %q%s", e.Cmd, e.Err, len(e.Data), data, suffix)
+}
+
+func (e *ResponseError) Unwrap() error {
+	return e.Err
+}
+
+// transferError wraps USB adapter read/write error. It matches ErrTransfer with errors.Is
+// and still unwraps to the original adapter error.
+type transferError struct {
+	msg string
+	err error
+}
+
+func (e *transferError) Error() string {
+	return fmt.Sprintf("%s: %v", e.msg, e.err)
+}
+
+func (e *transferError) Unwrap() error {
+	return e.err
+}
+
+func (e *transferError) Is(target error) bool {
+	return target == ErrTransfer //nolint:errorlint,goerr113
+}
+
+// sentinelErrors are sentinel errors kept by errors of remote device or recorded in traces,
+// so that errors.Is works with them. Error code is index in this list plus 1.
+// Only append to it to keep codes compatible.
+var sentinelErrors = []error{ErrTimeout, ErrTransfer, ErrReplayMismatch}
+
+// sentinelCode returns code of the sentinel error err wraps or 0 if none.
+func sentinelCode(err error) int {
+	for i, sentinel := range sentinelErrors {
+		if errors.Is(err, sentinel) {
+			return i + 1
+		}
+	}
+
+	return 0
+}
+
+// sentinelOf returns sentinel error of code or nil if code is not known.
+func sentinelOf(code int) error {
+	if code < 1 || code > len(sentinelErrors) {
+		return nil
+	}
+
+	return sentinelErrors[code-1]
+}
+
+// codedError is error rebuilt from its message and sentinel code, e.g. of remote device.
+type codedError struct {
+	msg      string
+	sentinel error // one of sentinelErrors or nil
+}
+
+func (e *codedError) Error() string {
+	return e.msg
+}
+
+func (e *codedError) Is(target error) bool {
+	return e.sentinel != nil && target == e.sentinel //nolint:errorlint,goerr113
+}
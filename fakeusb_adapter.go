@@ -1,5 +1,7 @@
 package skreader
 
+import "errors"
+
 // Assert FakeusbAdapter implements UsbDevice adapter interface
 var _ UsbAdapter = (*FakeusbAdapter)(nil)
 
@@ -39,6 +41,9 @@ func (f *FakeusbAdapter) Close() (err error) {
 }
 
 func (f *FakeusbAdapter) Read(buf []byte) (int, error) {
+	if f.ReadResponseIndex >= len(f.ReadResponse) {
+		return 0, errors.New("no more fake read responses")
+	}
 	r := f.ReadResponse[f.ReadResponseIndex]
 	n := copy(buf, r.Data)
 	f.ReadResponseIndex++
@@ -0,0 +1,82 @@
+package skreader
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FirmwareMainComponent is the index of the main firmware component in FirmwareInfo.Components.
+// Main firmware version is used for feature detection.
+const FirmwareMainComponent = 1
+
+// FirmwareComponent represents version and checksum of one of device firmware components.
+type FirmwareComponent struct {
+	Version  int
+	Checksum string // hexadecimal checksum as reported by device, e.g. "7881"
+}
+
+// FirmwareInfo represents versions of all device firmware components.
+type FirmwareInfo struct {
+	Components []FirmwareComponent
+
+	main int // main version found at fixed position when main component could not be parsed
+}
+
+// Main returns main firmware version or 0 if it is unknown.
+func (f FirmwareInfo) Main() int {
+	if len(f.Components) <= FirmwareMainComponent {
+		return f.main
+	}
+
+	return f.Components[FirmwareMainComponent].Version
+}
+
+// String returns all firmware components in the same form as device reports them.
+// Example: "20,C36E,27,7881,11,B216,14,50CC,17,74EC".
+func (f FirmwareInfo) String() string {
+	parts := make([]string, 0, len(f.Components)*2) //nolint:gomnd
+	for _, c := range f.Components {
+		parts = append(parts, fmt.Sprintf("%02d", c.Version), c.Checksum)
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// parseFirmwareInfo parses FV command response data which is a list of comma separated
+// version and checksum pairs, e.g. "20,C36E,27,7881,11,B216,14,50CC,17,74EC".
+//
+// Parsing is lenient, as untested firmwares may report it differently: well-formed pairs are kept
+// up to the first malformed or incomplete one. If main component is not among them, main version
+// is read from its usual position (chars 8 and 9). Error is returned only if main version is unknown.
+func parseFirmwareInfo(data string) (FirmwareInfo, error) {
+	const mainPos, mainLen = 8, 2
+
+	fields := strings.Split(data, ",")
+
+	info := FirmwareInfo{
+		Components: make([]FirmwareComponent, 0, len(fields)/2), //nolint:gomnd
+		main:       0,
+	}
+	for i := 0; i+1 < len(fields); i += 2 {
+		ver, err := strconv.Atoi(fields[i])
+		if err != nil {
+			break
+		}
+		info.Components = append(info.Components, FirmwareComponent{
+			Version:  ver,
+			Checksum: fields[i+1],
+		})
+	}
+
+	if len(info.Components) <= FirmwareMainComponent && len(data) >= mainPos+mainLen {
+		if ver, err := strconv.Atoi(data[mainPos : mainPos+mainLen]); err == nil {
+			info.main = ver
+		}
+	}
+	if info.Main() <= 0 {
+		return info, fmt.Errorf("invalid firmware info: %q", data)
+	}
+
+	return info, nil
+}
@@ -1,26 +1,29 @@
+//go:build cgo && !usbfs
+
 package skreader
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/google/gousb"
 )
 
-const (
-	IDVendor  = 0x0A41
-	IDProduct = 0x7003
-
-	EndpointNumOut = 0x02
-	EndpointNumIn  = 0x81
-)
-
 var _ UsbAdapter = (*GousbAdapter)(nil) // assert it implements UsbAdapter interface
 
 // GousbAdapter implements UsbAdapter interface using `gousb` library.
 // The gousb package is a Google's Go-like wrapper around `libusb` library which is required to be
 // installed on the target system. See more: https://github.com/libusb/libusb/wiki
+//
+// Zero value uses default options, use NewGousbAdapter to set them.
+//
+// It is used by default unless package is built without cgo or with `usbfs` build tag, see UsbfsAdapter.
 type GousbAdapter struct {
+	opts UsbAdapterOptions
+
 	ctx      *gousb.Context
 	dev      *gousb.Device
 	intf     *gousb.Interface
@@ -29,6 +32,11 @@ type GousbAdapter struct {
 	epOut    *gousb.OutEndpoint
 }
 
+// NewGousbAdapter creates GousbAdapter with given options.
+func NewGousbAdapter(opts UsbAdapterOptions) *GousbAdapter {
+	return &GousbAdapter{opts: opts} //nolint:exhaustruct
+}
+
 // Expose all used gousb functions as variables to be able to mock them in tests.
 var (
 	GousbNewContext = func() *gousb.Context { //nolint:all
@@ -37,6 +45,12 @@ var (
 	GousbOpenDeviceWithVIDPID = func(ctx *gousb.Context, vid gousb.ID, pid gousb.ID) (*gousb.Device, error) {
 		return ctx.OpenDeviceWithVIDPID(vid, pid)
 	}
+	GousbOpenDevices = func(ctx *gousb.Context, opener func(desc *gousb.DeviceDesc) bool) ([]*gousb.Device, error) {
+		return ctx.OpenDevices(opener)
+	}
+	GousbSerialNumber = func(d *gousb.Device) (string, error) {
+		return d.SerialNumber()
+	}
 	GousbDefaultInterface = func(d *gousb.Device) (*gousb.Interface, func(), error) {
 		return d.DefaultInterface()
 	}
@@ -52,20 +66,27 @@ var (
 	GousbProduct = func(d *gousb.Device) (string, error) {
 		return d.Product()
 	}
-	GousbRead = func(ep *gousb.InEndpoint, buf []byte) (int, error) {
-		return ep.Read(buf)
+	GousbRead = func(ctx context.Context, ep *gousb.InEndpoint, buf []byte) (int, error) {
+		return ep.ReadContext(ctx, buf)
 	}
-	GousbWrite = func(ep *gousb.OutEndpoint, buf []byte) (int, error) {
-		return ep.Write(buf)
+	GousbWrite = func(ctx context.Context, ep *gousb.OutEndpoint, buf []byte) (int, error) {
+		return ep.WriteContext(ctx, buf)
 	}
 )
 
 func (u *GousbAdapter) Open() (err error) {
 	u.ctx = GousbNewContext()
 
-	u.dev, err = GousbOpenDeviceWithVIDPID(u.ctx, IDVendor, IDProduct)
-	if err != nil {
-		return fmt.Errorf("could not open a device: %v", err)
+	if u.opts.Serial != "" || u.opts.Path != "" {
+		u.dev, err = u.openSelected()
+		if err != nil {
+			return err
+		}
+	} else {
+		u.dev, err = GousbOpenDeviceWithVIDPID(u.ctx, IDVendor, IDProduct)
+		if err != nil {
+			return fmt.Errorf("could not open a device: %v", err)
+		}
 	}
 	if u.dev == nil {
 		return errors.New("could not open a device, is it connected?")
@@ -89,6 +110,41 @@ func (u *GousbAdapter) Open() (err error) {
 	return nil
 }
 
+// openSelected opens the device selected by Serial and Path options. It returns nil if there is no such device.
+func (u *GousbAdapter) openSelected() (*gousb.Device, error) {
+	devs, err := GousbOpenDevices(u.ctx, func(desc *gousb.DeviceDesc) bool {
+		return isSekonic(desc) && (u.opts.Path == "" || usbPath(desc) == u.opts.Path)
+	})
+
+	var selected *gousb.Device
+	for _, dev := range devs {
+		if selected == nil && u.matchesSerial(dev) {
+			selected = dev
+
+			continue
+		}
+		_ = dev.Close()
+	}
+
+	if selected == nil && err != nil {
+		// Some devices could not be opened, the selected one may be among them.
+		return nil, fmt.Errorf("could not open a device: %v", err)
+	}
+
+	return selected, nil
+}
+
+// matchesSerial reports whether dev has serial number selected by Serial option (if set).
+func (u *GousbAdapter) matchesSerial(dev *gousb.Device) bool {
+	if u.opts.Serial == "" {
+		return true
+	}
+	serial, err := GousbSerialNumber(dev)
+
+	return err == nil && serial == u.opts.Serial
+}
+
+// Close releases the device. It is safe to call it more than once, adapter can be opened again then.
 func (u *GousbAdapter) Close() (err error) {
 	if u.intfDone != nil {
 		u.intfDone()
@@ -100,15 +156,47 @@ func (u *GousbAdapter) Close() (err error) {
 		err = u.ctx.Close()
 	}
 
+	u.ctx, u.dev, u.intf, u.intfDone, u.epIn, u.epOut = nil, nil, nil, nil, nil, nil
+
 	return err
 }
 
+// Read reads data from IN endpoint. If transfer is not completed within read timeout,
+// error wrapping ErrTimeout is returned.
 func (u *GousbAdapter) Read(buf []byte) (int, error) {
-	return GousbRead(u.epIn, buf)
+	timeout := u.opts.ReadTimeout
+	if timeout <= 0 {
+		timeout = UsbReadTimeoutDefault
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	n, err := GousbRead(ctx, u.epIn, buf)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return n, fmt.Errorf("%w: IN transfer not completed in %s", ErrTimeout, timeout)
+	}
+
+	return n, err
 }
 
+// Write writes data to OUT endpoint. If transfer is not completed within write timeout,
+// error wrapping ErrTimeout is returned.
 func (u *GousbAdapter) Write(buf []byte) (int, error) {
-	return GousbWrite(u.epOut, buf)
+	timeout := u.opts.WriteTimeout
+	if timeout <= 0 {
+		timeout = UsbWriteTimeoutDefault
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	n, err := GousbWrite(ctx, u.epOut, buf)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return n, fmt.Errorf("%w: OUT transfer not completed in %s", ErrTimeout, timeout)
+	}
+
+	return n, err
 }
 
 func (u *GousbAdapter) Manufacturer() (string, error) {
@@ -128,3 +216,57 @@ func (u *GousbAdapter) Product() (string, error) {
 
 	return product, nil
 }
+
+// ListGousbDevices returns all connected SEKONIC devices. Devices which could not be opened
+// (e.g. they are used by another process) are listed without Serial, Manufacturer and Product.
+func ListGousbDevices() ([]UsbDeviceInfo, error) {
+	ctx := GousbNewContext()
+	defer func() { _ = ctx.Close() }()
+
+	var infos []UsbDeviceInfo
+	devs, err := GousbOpenDevices(ctx, func(desc *gousb.DeviceDesc) bool {
+		if !isSekonic(desc) {
+			return false
+		}
+		infos = append(infos, UsbDeviceInfo{ //nolint:exhaustruct
+			Bus:     desc.Bus,
+			Address: desc.Address,
+			Path:    usbPath(desc),
+		})
+
+		return true
+	})
+	if err != nil && len(infos) == 0 {
+		return nil, fmt.Errorf("could not list devices: %v", err)
+	}
+
+	for _, dev := range devs {
+		for i := range infos {
+			info := &infos[i]
+			if info.Bus != dev.Desc.Bus || info.Address != dev.Desc.Address {
+				continue
+			}
+			info.Serial, _ = GousbSerialNumber(dev)
+			info.Manufacturer, _ = GousbManufacturer(dev)
+			info.Product, _ = GousbProduct(dev)
+		}
+		_ = dev.Close()
+	}
+
+	return infos, nil
+}
+
+// isSekonic reports whether USB device is SEKONIC device.
+func isSekonic(desc *gousb.DeviceDesc) bool {
+	return desc.Vendor == IDVendor && desc.Product == IDProduct
+}
+
+// usbPath returns USB port path of the device in the form "bus-port.port...", e.g. "1-2.3".
+func usbPath(desc *gousb.DeviceDesc) string {
+	ports := make([]string, 0, len(desc.Path))
+	for _, p := range desc.Path {
+		ports = append(ports, strconv.Itoa(p))
+	}
+
+	return fmt.Sprintf("%d-%s", desc.Bus, strings.Join(ports, "."))
+}
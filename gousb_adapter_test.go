@@ -1,6 +1,9 @@
+//go:build cgo && !usbfs
+
 package skreader_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -32,6 +35,25 @@ func (m *GousbMock) OpenDeviceWithVIDPID(ctx *gousb.Context, vid, pid gousb.ID)
 	return args.Get(0).(*gousb.Device), args.Error(1)
 }
 
+func (m *GousbMock) OpenDevices(ctx *gousb.Context, opener func(desc *gousb.DeviceDesc) bool) ([]*gousb.Device, error) {
+	args := m.Called(ctx)
+
+	var devs []*gousb.Device
+	for _, d := range args.Get(0).([]*gousb.Device) {
+		if opener(d.Desc) {
+			devs = append(devs, d)
+		}
+	}
+
+	return devs, args.Error(1)
+}
+
+func (m *GousbMock) SerialNumber(d *gousb.Device) (string, error) {
+	args := m.Called(d)
+
+	return args.String(0), args.Error(1)
+}
+
 func (m *GousbMock) DefaultInterface(d *gousb.Device) (*gousb.Interface, func(), error) {
 	args := m.Called(d)
 
@@ -62,13 +84,13 @@ func (m *GousbMock) Product(d *gousb.Device) (string, error) {
 	return args.String(0), args.Error(1)
 }
 
-func (m *GousbMock) Read(ep *gousb.InEndpoint, buf []byte) (int, error) {
+func (m *GousbMock) Read(_ context.Context, ep *gousb.InEndpoint, buf []byte) (int, error) {
 	args := m.Called(ep, buf)
 
 	return args.Int(0), args.Error(1)
 }
 
-func (m *GousbMock) Write(ep *gousb.OutEndpoint, buf []byte) (int, error) {
+func (m *GousbMock) Write(_ context.Context, ep *gousb.OutEndpoint, buf []byte) (int, error) {
 	args := m.Called(ep, buf)
 
 	return args.Int(0), args.Error(1)
@@ -87,6 +109,8 @@ func setupTest() (m *GousbMock, tearDown func()) {
 
 	skreader.GousbNewContext = m.NewContext
 	skreader.GousbOpenDeviceWithVIDPID = m.OpenDeviceWithVIDPID
+	skreader.GousbOpenDevices = m.OpenDevices
+	skreader.GousbSerialNumber = m.SerialNumber
 	skreader.GousbDefaultInterface = m.DefaultInterface
 	skreader.GousbDefaultInterface = m.DefaultInterface
 	skreader.GousbInEndpoint = m.InEndpoint
@@ -101,11 +125,34 @@ func setupTest() (m *GousbMock, tearDown func()) {
 	return m, tearDown
 }
 
+// expectDetection sets up responses to capabilities detection commands sent on connect.
+func expectDetection(m *GousbMock, model string) {
+	m.On("Write", mock.Anything, mock.Anything).Return(2, nil).Twice()
+	m.On("Read", mock.Anything, mock.Anything).Return(2, nil).Once().Run(func(args mock.Arguments) {
+		buf := args.Get(1).([]byte)
+		copy(buf, []byte{6, 48})
+	})
+	m.On("Read", mock.Anything, mock.Anything).Return(len(model)+6, nil).Once().Run(func(args mock.Arguments) {
+		buf := args.Get(1).([]byte)
+		copy(buf, []byte("MN@@@"+model+"\x00"))
+	})
+	m.On("Read", mock.Anything, mock.Anything).Return(2, nil).Once().Run(func(args mock.Arguments) {
+		buf := args.Get(1).([]byte)
+		copy(buf, []byte{6, 48})
+	})
+	m.On("Read", mock.Anything, mock.Anything).Return(44, nil).Once().Run(func(args mock.Arguments) {
+		buf := args.Get(1).([]byte)
+		copy(buf, []byte("FV@@@20,C36E,27,7881,11,B216,14,50CC,17,74EC")) //nolint:gocritic
+	})
+}
+
 //nolint:funlen
 func TestGousbAdapterHappyPath(t *testing.T) {
 	m, tearDown := setupTest()
 	defer tearDown()
 
+	expectDetection(m, "C-7000")
+
 	sk, err := skreader.NewDeviceWithAdapter(&skreader.GousbAdapter{})
 	assert.Nil(t, err, "NewDeviceWithAdapter() error")
 	assert.NotNil(t, sk, "NewDeviceWithAdapter() is nil")
@@ -129,67 +176,11 @@ func TestGousbAdapterHappyPath(t *testing.T) {
 	assert.Equal(t, "C-800", mn, "ModelName() invalid")
 
 	//
-	// Test SupportsMeasurementConfiguration()
+	// Test SupportsMeasurementConfiguration() and SupportsExtendedMeasurementConfiguration()
+	// answered from capabilities detected on connect.
 	//
 
-	m.On("Read", mock.Anything, mock.Anything).Return(2, nil).Once().Run(func(args mock.Arguments) {
-		buf := args.Get(1).([]byte)
-		copy(buf, []byte{6, 48})
-	})
-	m.On("Read", mock.Anything, mock.Anything).Return(15, nil).Once().Run(func(args mock.Arguments) {
-		buf := args.Get(1).([]byte)
-		copy(buf, []byte("MN@@@C-12345\x00\x00\x00\x00\x00")) //nolint:gocritic
-	})
-	assert.False(t, sk.SupportsMeasurementConfiguration(), "SupportsMeasurementConfiguration() invalid")
-
-	m.On("Read", mock.Anything, mock.Anything).Return(2, nil).Once().Run(func(args mock.Arguments) {
-		buf := args.Get(1).([]byte)
-		copy(buf, []byte{6, 48})
-	})
-	m.On("Read", mock.Anything, mock.Anything).Return(15, nil).Once().Run(func(args mock.Arguments) {
-		buf := args.Get(1).([]byte)
-		copy(buf, []byte("MN@@@C-800\x00\x00\x00\x00\x00")) //nolint:gocritic
-	})
 	assert.True(t, sk.SupportsMeasurementConfiguration(), "SupportsMeasurementConfiguration() invalid")
-
-	//
-	// Test SupportsExtendedMeasurementConfiguration()
-	//
-
-	m.On("Read", mock.Anything, mock.Anything).Return(2, nil).Once().Run(func(args mock.Arguments) {
-		buf := args.Get(1).([]byte)
-		copy(buf, []byte{6, 48})
-	})
-	m.On("Read", mock.Anything, mock.Anything).Return(15, nil).Once().Run(func(args mock.Arguments) {
-		buf := args.Get(1).([]byte)
-		copy(buf, []byte("MN@@@C-800\x00\x00\x00\x00\x00")) //nolint:gocritic
-	})
-	m.On("Read", mock.Anything, mock.Anything).Return(2, nil).Once().Run(func(args mock.Arguments) {
-		buf := args.Get(1).([]byte)
-		copy(buf, []byte{6, 48})
-	})
-	m.On("Read", mock.Anything, mock.Anything).Return(15, nil).Once().Run(func(args mock.Arguments) {
-		buf := args.Get(1).([]byte)
-		copy(buf, []byte("FV@@@20,C36E,27,7881,11,B216,14,50CC,17,74EC")) //nolint:gocritic
-	})
-	assert.False(t, sk.SupportsExtendedMeasurementConfiguration(), "SupportsExtendedMeasurementConfiguration() invalid")
-
-	m.On("Read", mock.Anything, mock.Anything).Return(2, nil).Once().Run(func(args mock.Arguments) {
-		buf := args.Get(1).([]byte)
-		copy(buf, []byte{6, 48})
-	})
-	m.On("Read", mock.Anything, mock.Anything).Return(15, nil).Once().Run(func(args mock.Arguments) {
-		buf := args.Get(1).([]byte)
-		copy(buf, []byte("MN@@@C-7000\x00\x00\x00\x00\x00")) //nolint:gocritic
-	})
-	m.On("Read", mock.Anything, mock.Anything).Return(2, nil).Once().Run(func(args mock.Arguments) {
-		buf := args.Get(1).([]byte)
-		copy(buf, []byte{6, 48})
-	})
-	m.On("Read", mock.Anything, mock.Anything).Return(15, nil).Once().Run(func(args mock.Arguments) {
-		buf := args.Get(1).([]byte)
-		copy(buf, []byte("FV@@@20,C36E,27,7881,11,B216,14,50CC,17,74EC")) //nolint:gocritic
-	})
 	assert.True(t, sk.SupportsExtendedMeasurementConfiguration(), "SupportsExtendedMeasurementConfiguration() invalid")
 
 	//
@@ -200,7 +191,7 @@ func TestGousbAdapterHappyPath(t *testing.T) {
 		buf := args.Get(1).([]byte)
 		copy(buf, []byte{6, 48})
 	})
-	m.On("Read", mock.Anything, mock.Anything).Return(15, nil).Once().Run(func(args mock.Arguments) {
+	m.On("Read", mock.Anything, mock.Anything).Return(44, nil).Once().Run(func(args mock.Arguments) {
 		buf := args.Get(1).([]byte)
 		copy(buf, []byte("FV@@@20,C36E,27,7881,11,B216,14,50CC,17,74EC")) //nolint:gocritic
 	})
@@ -317,16 +308,6 @@ func TestGousbAdapterHappyPath(t *testing.T) {
 	// SetMeasurementConfiguration
 	//
 
-	// ModelNumber OK
-	m.On("Read", mock.Anything, mock.Anything).Return(2, nil).Once().Run(func(args mock.Arguments) {
-		buf := args.Get(1).([]byte)
-		copy(buf, []byte{6, 48})
-	})
-	m.On("Read", mock.Anything, mock.Anything).Return(15, nil).Once().Run(func(args mock.Arguments) {
-		buf := args.Get(1).([]byte)
-		copy(buf, []byte("MN@@@C-7000\x00\x00\x00\x00\x00")) //nolint:gocritic
-	})
-
 	// MeasuringMode OK
 	m.On("Read", mock.Anything, mock.Anything).Return(2, nil).Once().Run(func(args mock.Arguments) {
 		buf := args.Get(1).([]byte)
@@ -346,25 +327,6 @@ func TestGousbAdapterHappyPath(t *testing.T) {
 		copy(buf, []byte("SS")) //nolint:gocritic
 	})
 
-	// ModelNumber OK
-	m.On("Read", mock.Anything, mock.Anything).Return(2, nil).Once().Run(func(args mock.Arguments) {
-		buf := args.Get(1).([]byte)
-		copy(buf, []byte{6, 48})
-	})
-	m.On("Read", mock.Anything, mock.Anything).Return(15, nil).Once().Run(func(args mock.Arguments) {
-		buf := args.Get(1).([]byte)
-		copy(buf, []byte("MN@@@C-7000\x00\x00\x00\x00\x00")) //nolint:gocritic
-	})
-	// FirmwareVersion OK
-	m.On("Read", mock.Anything, mock.Anything).Return(2, nil).Once().Run(func(args mock.Arguments) {
-		buf := args.Get(1).([]byte)
-		copy(buf, []byte{6, 48})
-	})
-	m.On("Read", mock.Anything, mock.Anything).Return(15, nil).Once().Run(func(args mock.Arguments) {
-		buf := args.Get(1).([]byte)
-		copy(buf, []byte("FV@@@20,C36E,27,7881,11,B216,14,50CC,17,74EC")) //nolint:gocritic
-	})
-
 	// SetFov OK
 	m.On("Read", mock.Anything, mock.Anything).Return(2, nil).Once().Run(func(args mock.Arguments) {
 		buf := args.Get(1).([]byte)
@@ -406,7 +368,7 @@ func TestGousbAdapterHappyPath(t *testing.T) {
 	// Test MeasurementResult()
 	//
 
-	// Invalid data size
+	// Truncated data
 	m.On("Read", mock.Anything, mock.Anything).Return(2, nil).Once().Run(func(args mock.Arguments) {
 		buf := args.Get(1).([]byte)
 		copy(buf, []byte{6, 48})
@@ -415,20 +377,27 @@ func TestGousbAdapterHappyPath(t *testing.T) {
 		buf := args.Get(1).([]byte)
 		copy(buf, skreader.Testdata)
 	})
+	m.On("Read", mock.Anything, mock.Anything).Return(0, nil).Once()
 	_, err = sk.MeasurementResult()
-	assert.NotNil(t, err, "MeasurementResult() error")
+	assert.ErrorIs(t, err, skreader.ErrTruncatedResponse, "MeasurementResult() error")
 
-	// Correct data
+	// Correct extended data split into two transfers
+	data := testdataExtended(skreader.Testdata, 88)
 	m.On("Read", mock.Anything, mock.Anything).Return(2, nil).Once().Run(func(args mock.Arguments) {
 		buf := args.Get(1).([]byte)
 		copy(buf, []byte{6, 48})
 	})
-	m.On("Read", mock.Anything, mock.Anything).Return(2380, nil).Once().Run(func(args mock.Arguments) {
+	m.On("Read", mock.Anything, mock.Anything).Return(2048, nil).Once().Run(func(args mock.Arguments) {
 		buf := args.Get(1).([]byte)
-		copy(buf, skreader.Testdata)
+		copy(buf, data[:2048])
 	})
-	_, err = sk.MeasurementResult()
+	m.On("Read", mock.Anything, mock.Anything).Return(len(data)-2048, nil).Once().Run(func(args mock.Arguments) {
+		buf := args.Get(1).([]byte)
+		copy(buf, data[2048:])
+	})
+	meas, err := sk.MeasurementResult()
 	assert.Nil(t, err, "MeasurementResult() error")
+	assert.NotNil(t, meas.TM30, "MeasurementResult() TM30")
 
 	err = sk.Close()
 	assert.Nil(t, err, "Close() error")
@@ -438,6 +407,8 @@ func TestGousbAdapterReadErrors(t *testing.T) { //nolint:funlen
 	m, tearDown := setupTest()
 	defer tearDown()
 
+	expectDetection(m, "C-800")
+
 	sk, err := skreader.NewDeviceWithAdapter(&skreader.GousbAdapter{})
 	assert.Nil(t, err, "NewDeviceWithAdapter() error")
 	assert.NotNil(t, sk, "NewDeviceWithAdapter() is nil")
@@ -526,6 +497,8 @@ func TestGousbAdapterReadNoData(t *testing.T) {
 	m, tearDown := setupTest()
 	defer tearDown()
 
+	expectDetection(m, "C-800")
+
 	sk, err := skreader.NewDeviceWithAdapter(&skreader.GousbAdapter{})
 	assert.Nil(t, err, "NewDeviceWithAdapter() error")
 	assert.NotNil(t, sk, "NewDeviceWithAdapter() is nil")
@@ -553,6 +526,8 @@ func TestGousbAdapterReadDataOverflow(t *testing.T) {
 	m, tearDown := setupTest()
 	defer tearDown()
 
+	expectDetection(m, "C-800")
+
 	sk, err := skreader.NewDeviceWithAdapter(&skreader.GousbAdapter{})
 	assert.Nil(t, err, "NewDeviceWithAdapter() error")
 	assert.NotNil(t, sk, "NewDeviceWithAdapter() is nil")
@@ -580,6 +555,8 @@ func TestGousbAdapterWriteErrors(t *testing.T) {
 	m, tearDown := setupTest()
 	defer tearDown()
 
+	expectDetection(m, "C-800")
+
 	sk, err := skreader.NewDeviceWithAdapter(&skreader.GousbAdapter{})
 	assert.Nil(t, err, "NewDeviceWithAdapter() error")
 	assert.NotNil(t, sk, "NewDeviceWithAdapter() is nil")
@@ -599,6 +576,8 @@ func TestGousbAdapterWriteSizeErrors(t *testing.T) {
 	m, tearDown := setupTest()
 	defer tearDown()
 
+	expectDetection(m, "C-800")
+
 	sk, err := skreader.NewDeviceWithAdapter(&skreader.GousbAdapter{})
 	assert.Nil(t, err, "NewDeviceWithAdapter() error")
 	assert.NotNil(t, sk, "NewDeviceWithAdapter() is nil")
@@ -613,3 +592,105 @@ func TestGousbAdapterWriteSizeErrors(t *testing.T) {
 	assert.NotNil(t, err, "FirmwareVersion() error")
 	assert.Equal(t, 0, fw, "FirmwareVersion() invalid")
 }
+
+func TestGousbAdapterTimeout(t *testing.T) {
+	m, tearDown := setupTest()
+	defer tearDown()
+
+	expectDetection(m, "C-800")
+
+	adapter := skreader.NewGousbAdapter(skreader.UsbAdapterOptions{
+		ReadTimeout:  time.Duration(10) * time.Millisecond,
+		WriteTimeout: time.Duration(10) * time.Millisecond,
+	})
+	sk, err := skreader.NewDeviceWithAdapter(adapter)
+	assert.Nil(t, err, "NewDeviceWithAdapter() error")
+
+	// Transfers are blocked until canceled like with unplugged device.
+	blocked := func(ctx context.Context) (int, error) {
+		<-ctx.Done()
+
+		return 0, gousb.TransferCancelled
+	}
+	skreader.GousbRead = func(ctx context.Context, _ *gousb.InEndpoint, _ []byte) (int, error) {
+		return blocked(ctx)
+	}
+	skreader.GousbWrite = func(ctx context.Context, _ *gousb.OutEndpoint, _ []byte) (int, error) {
+		return blocked(ctx)
+	}
+
+	_, err = adapter.Read(make([]byte, 2))
+	assert.ErrorIs(t, err, skreader.ErrTimeout, "Read() error")
+
+	_, err = adapter.Write([]byte("ST"))
+	assert.ErrorIs(t, err, skreader.ErrTimeout, "Write() error")
+
+	_, err = sk.State()
+	assert.ErrorIs(t, err, skreader.ErrTimeout, "State() error")
+	assert.ErrorIs(t, err, skreader.ErrTransfer, "State() error")
+
+	// WaitReady does not hang either.
+	err = sk.WaitReady(time.Duration(50)*time.Millisecond, time.Duration(10)*time.Millisecond)
+	assert.ErrorIs(t, err, skreader.ErrTimeout, "WaitReady() error")
+}
+
+// connectedDevices returns two SEKONIC devices and one device of another vendor.
+func connectedDevices(m *GousbMock) (sk1, sk2, other *gousb.Device) {
+	sk1 = &gousb.Device{Desc: &gousb.DeviceDesc{Bus: 1, Address: 5, Path: []int{2, 3}, Vendor: 0x0A41, Product: 0x7003}} //nolint:exhaustruct
+	sk2 = &gousb.Device{Desc: &gousb.DeviceDesc{Bus: 2, Address: 7, Path: []int{1}, Vendor: 0x0A41, Product: 0x7003}}    //nolint:exhaustruct
+	other = &gousb.Device{Desc: &gousb.DeviceDesc{Bus: 1, Address: 2, Path: []int{4}, Vendor: 0x046D, Product: 0xC52B}}  //nolint:exhaustruct
+
+	m.On("OpenDevices", mock.Anything).Return([]*gousb.Device{other, sk1, sk2}, nil)
+	m.On("SerialNumber", sk1).Return("SN0001", nil)
+	m.On("SerialNumber", sk2).Return("SN0002", nil)
+
+	return sk1, sk2, other
+}
+
+func TestListDevices(t *testing.T) {
+	m, tearDown := setupTest()
+	defer tearDown()
+
+	connectedDevices(m)
+
+	devs, err := skreader.ListDevices()
+	assert.Nil(t, err, "ListDevices() error")
+	assert.Equal(t, []skreader.UsbDeviceInfo{
+		{Bus: 1, Address: 5, Path: "1-2.3", Serial: "SN0001", Manufacturer: "TheManufacturer", Product: "TheProduct"},
+		{Bus: 2, Address: 7, Path: "2-1", Serial: "SN0002", Manufacturer: "TheManufacturer", Product: "TheProduct"},
+	}, devs, "ListDevices() invalid")
+}
+
+func TestGousbAdapterSelectDevice(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		opts    skreader.UsbAdapterOptions
+		want    int // index of connected SEKONIC device, -1 if none
+		wantErr bool
+	}{
+		{name: "by serial", opts: skreader.UsbAdapterOptions{Serial: "SN0002"}, want: 1, wantErr: false},            //nolint:exhaustruct
+		{name: "by path", opts: skreader.UsbAdapterOptions{Path: "1-2.3"}, want: 0, wantErr: false},                 //nolint:exhaustruct
+		{name: "by both", opts: skreader.UsbAdapterOptions{Serial: "SN0002", Path: "2-1"}, want: 1, wantErr: false}, //nolint:exhaustruct
+		{name: "unknown serial", opts: skreader.UsbAdapterOptions{Serial: "SN0003"}, want: -1, wantErr: true},       //nolint:exhaustruct
+		{name: "other vendor path", opts: skreader.UsbAdapterOptions{Path: "1-4"}, want: -1, wantErr: true},         //nolint:exhaustruct
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			m, tearDown := setupTest()
+			defer tearDown()
+
+			sk1, sk2, _ := connectedDevices(m)
+
+			err := skreader.NewGousbAdapter(tt.opts).Open()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Open() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for i, dev := range []*gousb.Device{sk1, sk2} {
+				if i == tt.want {
+					m.AssertCalled(t, "DefaultInterface", dev)
+				} else {
+					m.AssertNotCalled(t, "DefaultInterface", dev)
+				}
+			}
+		})
+	}
+}
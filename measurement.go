@@ -9,6 +9,8 @@ import (
 // Measurement represents a measurement data from SEKONIC device.
 // Data format is based on original C-7000 SDK from SEKONIC (distributed only as Windows DLL).
 type Measurement struct {
+	Header MeasurementHeader // Measurement title and settings used by device
+
 	Tristimulus      TristimulusValue        // Tristimulus values in XYZ color space
 	ColorTemperature ColorTemperatureValue   // Correlated Color Temperature
 	Illuminance      IlluminanceValue        // Illuminance
@@ -22,10 +24,56 @@ type Measurement struct {
 	SpectralData5nm [81]DecimalValue  // Spectral Data (5nm)
 	SpectralData1nm [401]DecimalValue // Spectral Data (1nm)
 	PeakWavelength  int               // Peak Wavelength (380...780nm)
+
+	TM30 *TM30Value // ANSI/IES TM-30 color rendition (extended data only, nil if not available)
+	SSI  *SSIValue  // Spectral Similarity Index (extended data only, nil if not available)
+	TLCI *TLCIValue // Television Lighting Consistency Index (extended data only, nil if not available)
+
+	Config *AppliedMeasurementConfig // configuration applied by Measure or Session, nil if measured otherwise
 }
 
 const (
-	MeasurementDataValidSize = 2380 // tested on C-7000, C-800, C-700
+	MeasurementDataValidSize    = 2380 // tested on C-7000, C-800, C-700
+	MeasurementDataExtendedSize = 3047 // C-7000 FW > 25 (base data followed by extended data)
+)
+
+// Measurement data packet layout. Values are big-endian float32 (float64 for tristimulus) numbers.
+// Single values and CRI/TM-30 values are preceded with comma separator, so each of them takes 5 bytes.
+// Spectral data and TM-30 samples are packed without separators, so each of them takes 4 bytes.
+const (
+	offsetTcp                 = 50
+	offsetDeltaUv             = 55
+	offsetLux                 = 271
+	offsetFootCandle          = 276
+	offsetTristimulusX        = 281
+	offsetTristimulusY        = 290
+	offsetTristimulusZ        = 299
+	offsetCIE1931X            = 308
+	offsetCIE1931Y            = 313
+	offsetCIE1976Ud           = 328
+	offsetCIE1976Vd           = 333
+	offsetDWLWavelength       = 338
+	offsetDWLExcitationPurity = 343
+	offsetCRIRa               = 348
+	offsetCRIRi               = 353
+	offsetSpectralData5nm     = 428
+	offsetSpectralData1nm     = 753
+	offsetPPFD                = 2376
+
+	// Extended data (C-7000 FW > 25).
+	offsetTM30Rf        = 2381
+	offsetTM30Rg        = 2386
+	offsetTM30HueBinRf  = 2391
+	offsetTM30HueBinRcs = 2471
+	offsetTM30HueBinRhs = 2551
+	offsetTM30SampleRf  = 2631
+	offsetSSITungsten   = 3028
+	offsetSSIDaylight   = 3033
+	offsetTLCIQa        = 3038
+	offsetTLCITLMF      = 3043
+
+	separatedValueSize = 5 // comma + float32
+	packedValueSize    = 4 // float32
 )
 
 // ValueRange indicates if a measurement result value is within/over/under limits.
@@ -112,18 +160,77 @@ type ColorRenditionIndexesValue struct {
 	Ri [15]DecimalValue
 }
 
-// TODO: Not implemented here but available for C-7000 FW > 25 extended measurement data:
-// TM30, SSI, TLCI
+// TM30Value represents ANSI/IES TM-30 color rendition values.
+// Hue angle bins are ordered from bin 1 (0°...22.5°) to bin 16 (337.5°...360°).
+type TM30Value struct {
+	Rf        DecimalValue     // Fidelity Index
+	Rg        DecimalValue     // Gamut Index
+	HueBinRf  [16]DecimalValue // Local Color Fidelity per hue angle bin (Rf,hj)
+	HueBinRcs [16]DecimalValue // Local Chroma Shift per hue angle bin in % (Rcs,hj)
+	HueBinRhs [16]DecimalValue // Local Hue Shift per hue angle bin (Rhs,hj)
+	SampleRf  [99]DecimalValue // Color Fidelity per color evaluation sample (Rf,CESi)
+}
+
+// SSIValue represents Spectral Similarity Index values against standard reference illuminants.
+type SSIValue struct {
+	Tungsten DecimalValue // SSI against 3200K tungsten reference (SSIt)
+	Daylight DecimalValue // SSI against 5600K daylight reference (SSId)
+}
+
+// TLCIValue represents Television Lighting Consistency Index (TLCI-2012) values.
+type TLCIValue struct {
+	Qa   DecimalValue // Television Lighting Consistency Index
+	TLMF DecimalValue // Television Luminaire Matching Factor
+}
+
+// illuminanceLimits represents valid ranges of illuminance values for specific measuring mode.
+type illuminanceLimits struct {
+	luxLow, luxHigh float64
+	fcLow, fcHigh   float64
+}
+
+// Magic numbers for limits are based on original C-7000 SDK (ambient) and C-7000 specs (flash).
+var (
+	ambientIlluminanceLimits = illuminanceLimits{100, 200000, 0.093000002205371857, 18580.607421875}
+	flashIlluminanceLimits   = illuminanceLimits{20, 20500, 1.86, 1905}
+)
 
 // NewMeasurementFromBytes creates a new Measurement instance from the given raw
 // binary response from SEKONIC device.
-// Note: currently only ambient measuring mode results are supported.
+// Measurement title and settings reported by device are parsed to Header.
+// If data contains extended measurement data (C-7000 FW > 25), it is parsed as well.
+// Note: only ambient measuring mode results are supported, use NewFlashMeasurementFromBytes
+// for flash measuring modes results.
+func NewMeasurementFromBytes(data []byte) (*Measurement, error) {
+	return parseMeasurement(data, ambientIlluminanceLimits)
+}
+
+// illuminanceLimitsOf returns illuminance limits of the measuring mode.
+func illuminanceLimitsOf(mode SkMeasuringMode) illuminanceLimits {
+	if mode.IsFlash() {
+		return flashIlluminanceLimits
+	}
+
+	return ambientIlluminanceLimits
+}
+
+// parseMeasurement parses the given raw binary response from SEKONIC device using
+// illuminance limits of the measuring mode used. Extended data is parsed if data is long enough.
+func parseMeasurement(data []byte, limits illuminanceLimits) (*Measurement, error) {
+	return decodeMeasurement(data, limits, len(data) >= MeasurementDataExtendedSize)
+}
+
+// decodeMeasurement decodes the given raw binary response from SEKONIC device using
+// illuminance limits of the measuring mode used. If extended is true, data must contain extended data.
 //
 //nolint:exhaustruct,funlen,gomnd,gocyclo
-func NewMeasurementFromBytes(data []byte) (*Measurement, error) {
+func decodeMeasurement(data []byte, limits illuminanceLimits, extended bool) (*Measurement, error) {
 	if len(data) < MeasurementDataValidSize {
 		return nil, fmt.Errorf("invalid measurement data size: %d < %d bytes", len(data), MeasurementDataValidSize)
 	}
+	if extended && len(data) < MeasurementDataExtendedSize {
+		return nil, fmt.Errorf("invalid extended measurement data size: %d < %d bytes", len(data), MeasurementDataExtendedSize)
+	}
 
 	// Parse binary data to struct.
 	//
@@ -134,25 +241,28 @@ func NewMeasurementFromBytes(data []byte) (*Measurement, error) {
 
 	m := &Measurement{}
 
+	// Measurement title and settings
+	m.Header = parseHeader(data)
+
 	// Color temperature and deviation from the Planckian locus
-	m.ColorTemperature.Tcp = toDecimalValue(parseFloat32(data, 50), 1563, 100000, 0)
-	m.ColorTemperature.DeltaUv = toDecimalValue(parseFloat32(data, 55), -0.1, 0.1, 4)
+	m.ColorTemperature.Tcp = toDecimalValue(parseFloat32(data, offsetTcp), 1563, 100000, 0)
+	m.ColorTemperature.DeltaUv = toDecimalValue(parseFloat32(data, offsetDeltaUv), -0.1, 0.1, 4)
 	if m.ColorTemperature.DeltaUv.Range != RangeOk { // limit the CCT value (C-800 returns Tcp=50000 value instead of "Over" as C-7000 does)
 		m.ColorTemperature.Tcp.Range = m.ColorTemperature.DeltaUv.Range
 	}
 
 	// Illuminance values in Lux and foot-candle units
-	m.Illuminance.Lux = parseLuxToDecimalValue(data, 271, 100, 200000)
-	m.Illuminance.FootCandle = parseLuxToDecimalValue(data, 276, 0.093000002205371857, 18580.607421875)
+	m.Illuminance.Lux = parseLuxToDecimalValue(data, offsetLux, limits.luxLow, limits.luxHigh)
+	m.Illuminance.FootCandle = parseLuxToDecimalValue(data, offsetFootCandle, limits.fcLow, limits.fcHigh)
 
 	// Tristimulus values in XYZ color space
-	m.Tristimulus.X = toDecimalValue(parseFloat64(data, 281), 0, 1000000, 4)
-	m.Tristimulus.Y = toDecimalValue(parseFloat64(data, 290), 0, 1000000, 4)
-	m.Tristimulus.Z = toDecimalValue(parseFloat64(data, 299), 0, 1000000, 4)
+	m.Tristimulus.X = toDecimalValue(parseFloat64(data, offsetTristimulusX), 0, 1000000, 4)
+	m.Tristimulus.Y = toDecimalValue(parseFloat64(data, offsetTristimulusY), 0, 1000000, 4)
+	m.Tristimulus.Z = toDecimalValue(parseFloat64(data, offsetTristimulusZ), 0, 1000000, 4)
 
 	// CIE1931 (x, y, z) chromaticity coordinates
-	m.CIE1931.X = toDecimalValue(parseFloat32(data, 308), 0, 1, 4)
-	m.CIE1931.Y = toDecimalValue(parseFloat32(data, 313), 0, 1, 4)
+	m.CIE1931.X = toDecimalValue(parseFloat32(data, offsetCIE1931X), 0, 1, 4)
+	m.CIE1931.Y = toDecimalValue(parseFloat32(data, offsetCIE1931Y), 0, 1, 4)
 	if m.CIE1931.X.Range != RangeOk {
 		m.CIE1931.Z.Range = m.CIE1931.X.Range
 	} else if m.CIE1931.Y.Range != RangeOk {
@@ -162,17 +272,17 @@ func NewMeasurementFromBytes(data []byte) (*Measurement, error) {
 	}
 
 	// CIE1976 (u', v') chromaticity coordinates
-	m.CIE1976.Ud = toDecimalValue(parseFloat32(data, 328), 0, 1, 4)
-	m.CIE1976.Vd = toDecimalValue(parseFloat32(data, 333), 0, 1, 4)
+	m.CIE1976.Ud = toDecimalValue(parseFloat32(data, offsetCIE1976Ud), 0, 1, 4)
+	m.CIE1976.Vd = toDecimalValue(parseFloat32(data, offsetCIE1976Vd), 0, 1, 4)
 
 	// Dominant Wavelength
-	m.DWL.Wavelength = toDecimalValue(parseFloat32(data, 338), -780, 780, 0)
-	m.DWL.ExcitationPurity = toDecimalValue(parseFloat32(data, 343), 0, 100, 1)
+	m.DWL.Wavelength = toDecimalValue(parseFloat32(data, offsetDWLWavelength), -780, 780, 0)
+	m.DWL.ExcitationPurity = toDecimalValue(parseFloat32(data, offsetDWLExcitationPurity), 0, 100, 1)
 
 	// CRI (Ra, Ri)
-	m.ColorRenditionIndexes.Ra = toDecimalValue(parseFloat32(data, 348), -100, 100, 1)
+	m.ColorRenditionIndexes.Ra = toDecimalValue(parseFloat32(data, offsetCRIRa), -100, 100, 1)
 	for i := range m.ColorRenditionIndexes.Ri {
-		m.ColorRenditionIndexes.Ri[i] = toDecimalValue(parseFloat32(data, 353+i*5), -100, 100, 1)
+		m.ColorRenditionIndexes.Ri[i] = toDecimalValue(parseFloat32(data, offsetCRIRi+i*separatedValueSize), -100, 100, 1)
 	}
 
 	// Boundaries check
@@ -195,12 +305,12 @@ func NewMeasurementFromBytes(data []byte) (*Measurement, error) {
 		}
 	} else {
 		for i := range m.SpectralData5nm {
-			m.SpectralData5nm[i] = toDecimalValue(parseFloat32(data, 428+i*4), 0, 9999.9, 8)
+			m.SpectralData5nm[i] = toDecimalValue(parseFloat32(data, offsetSpectralData5nm+i*packedValueSize), 0, 9999.9, 8)
 		}
 		m.PeakWavelength = 380
 		maxval := m.SpectralData1nm[0].Val
 		for i := range m.SpectralData1nm {
-			m.SpectralData1nm[i] = toDecimalValue(parseFloat32(data, 753+i*4), 0, 9999.9, 8)
+			m.SpectralData1nm[i] = toDecimalValue(parseFloat32(data, offsetSpectralData1nm+i*packedValueSize), 0, 9999.9, 8)
 			if m.SpectralData1nm[i].Val > 0 && m.SpectralData1nm[i].Val > maxval {
 				maxval = m.SpectralData1nm[i].Val
 				m.PeakWavelength = 380 + i
@@ -208,7 +318,15 @@ func NewMeasurementFromBytes(data []byte) (*Measurement, error) {
 		}
 	}
 
-	m.PPFD = toDecimalValue(parseFloat32(data, 2376), 0, 9999.9, 1)
+	m.PPFD = toDecimalValue(parseFloat32(data, offsetPPFD), 0, 9999.9, 1)
+
+	// Extended data
+
+	if extended {
+		m.TM30 = parseTM30(data)
+		m.SSI = parseSSI(data)
+		m.TLCI = parseTLCI(data)
+	}
 
 	// Boundaries extra check
 
@@ -226,6 +344,7 @@ func NewMeasurementFromBytes(data []byte) (*Measurement, error) {
 		for i := range m.ColorRenditionIndexes.Ri {
 			m.ColorRenditionIndexes.Ri[i].Range = RangeUnder
 		}
+		m.setExtendedRange(RangeUnder)
 	}
 
 	if m.ColorTemperature.Tcp.Range != RangeOk {
@@ -234,11 +353,93 @@ func NewMeasurementFromBytes(data []byte) (*Measurement, error) {
 		for i := range m.ColorRenditionIndexes.Ri {
 			m.ColorRenditionIndexes.Ri[i].Range = m.ColorTemperature.Tcp.Range
 		}
+		m.setExtendedRange(m.ColorTemperature.Tcp.Range)
 	}
 
 	return m, nil
 }
 
+// parseTM30 parses TM-30 values from the extended measurement data.
+//
+// Extended data follows the base data and uses the same conventions: comma separated
+// big-endian float32 values, with per-sample values packed without separators like spectral data.
+//
+//nolint:gomnd
+func parseTM30(data []byte) *TM30Value {
+	tm30 := &TM30Value{}
+
+	tm30.Rf = toDecimalValue(parseFloat32(data, offsetTM30Rf), 0, 100, 0)
+	tm30.Rg = toDecimalValue(parseFloat32(data, offsetTM30Rg), 0, 200, 0)
+	for i := range tm30.HueBinRf {
+		tm30.HueBinRf[i] = toDecimalValue(parseFloat32(data, offsetTM30HueBinRf+i*separatedValueSize), 0, 100, 0)
+	}
+	for i := range tm30.HueBinRcs {
+		tm30.HueBinRcs[i] = toDecimalValue(parseFloat32(data, offsetTM30HueBinRcs+i*separatedValueSize), -100, 100, 0)
+	}
+	for i := range tm30.HueBinRhs {
+		tm30.HueBinRhs[i] = toDecimalValue(parseFloat32(data, offsetTM30HueBinRhs+i*separatedValueSize), -1, 1, 2)
+	}
+	for i := range tm30.SampleRf {
+		tm30.SampleRf[i] = toDecimalValue(parseFloat32(data, offsetTM30SampleRf+i*packedValueSize), 0, 100, 0)
+	}
+
+	return tm30
+}
+
+// parseSSI parses SSI values from the extended measurement data.
+//
+//nolint:gomnd
+func parseSSI(data []byte) *SSIValue {
+	return &SSIValue{
+		Tungsten: toDecimalValue(parseFloat32(data, offsetSSITungsten), 0, 100, 0),
+		Daylight: toDecimalValue(parseFloat32(data, offsetSSIDaylight), 0, 100, 0),
+	}
+}
+
+// parseTLCI parses TLCI values from the extended measurement data.
+//
+//nolint:gomnd
+func parseTLCI(data []byte) *TLCIValue {
+	return &TLCIValue{
+		Qa:   toDecimalValue(parseFloat32(data, offsetTLCIQa), 0, 100, 0),
+		TLMF: toDecimalValue(parseFloat32(data, offsetTLCITLMF), 0, 100, 0),
+	}
+}
+
+// setExtendedRange overrides validity indicator of all available extended data values.
+// Like CRI, extended data values are not valid when color temperature is not valid.
+func (m *Measurement) setExtendedRange(r ValueRange) {
+	if m.TM30 != nil {
+		m.TM30.setRange(r)
+	}
+	if m.SSI != nil {
+		m.SSI.Tungsten.Range = r
+		m.SSI.Daylight.Range = r
+	}
+	if m.TLCI != nil {
+		m.TLCI.Qa.Range = r
+		m.TLCI.TLMF.Range = r
+	}
+}
+
+// setRange overrides validity indicator of all TM-30 values.
+func (v *TM30Value) setRange(r ValueRange) {
+	v.Rf.Range = r
+	v.Rg.Range = r
+	for i := range v.HueBinRf {
+		v.HueBinRf[i].Range = r
+	}
+	for i := range v.HueBinRcs {
+		v.HueBinRcs[i].Range = r
+	}
+	for i := range v.HueBinRhs {
+		v.HueBinRhs[i].Range = r
+	}
+	for i := range v.SampleRf {
+		v.SampleRf[i].Range = r
+	}
+}
+
 // String returns limited string representation of the Measurement instance.
 // Used mostly for debugging.
 func (m *Measurement) String() string {
@@ -0,0 +1,115 @@
+package skreader
+
+import (
+	"encoding"
+	"encoding/binary"
+	"math"
+)
+
+var _ encoding.BinaryMarshaler = (*Measurement)(nil) // assert it implements encoding.BinaryMarshaler interface
+
+// measurementPacketPrefix is the beginning of NR command response.
+var measurementPacketPrefix = []byte("NRB@@")
+
+// MarshalBinary encodes the Measurement instance to raw binary data in the same layout as SEKONIC
+// device sends in response to NR command, so that NewMeasurementFromBytes(m.MarshalBinary()) returns
+// the same measurement again.
+//
+// The result is MeasurementDataValidSize bytes long. If any of extended data values (TM-30, SSI, TLCI)
+// is set, extended data is added and the result is MeasurementDataExtendedSize bytes long.
+// Packet fields which are not part of Measurement (or its Header) are left zeroed.
+//
+// Values are written as is, derived values (CIE1931.Z, PeakWavelength) and validity indicators are not
+// encoded since they are calculated by the parser.
+func (m *Measurement) MarshalBinary() ([]byte, error) {
+	size := MeasurementDataValidSize
+	if m.TM30 != nil || m.SSI != nil || m.TLCI != nil {
+		size = MeasurementDataExtendedSize
+	}
+
+	data := make([]byte, size)
+	copy(data, measurementPacketPrefix)
+
+	m.Header.putHeader(data)
+
+	putFloat32(data, offsetTcp, m.ColorTemperature.Tcp.Val)
+	putFloat32(data, offsetDeltaUv, m.ColorTemperature.DeltaUv.Val)
+
+	putFloat32(data, offsetLux, m.Illuminance.Lux.Val)
+	putFloat32(data, offsetFootCandle, m.Illuminance.FootCandle.Val)
+
+	putFloat64(data, offsetTristimulusX, m.Tristimulus.X.Val)
+	putFloat64(data, offsetTristimulusY, m.Tristimulus.Y.Val)
+	putFloat64(data, offsetTristimulusZ, m.Tristimulus.Z.Val)
+
+	putFloat32(data, offsetCIE1931X, m.CIE1931.X.Val)
+	putFloat32(data, offsetCIE1931Y, m.CIE1931.Y.Val)
+
+	putFloat32(data, offsetCIE1976Ud, m.CIE1976.Ud.Val)
+	putFloat32(data, offsetCIE1976Vd, m.CIE1976.Vd.Val)
+
+	putFloat32(data, offsetDWLWavelength, m.DWL.Wavelength.Val)
+	putFloat32(data, offsetDWLExcitationPurity, m.DWL.ExcitationPurity.Val)
+
+	putFloat32(data, offsetCRIRa, m.ColorRenditionIndexes.Ra.Val)
+	for i, v := range m.ColorRenditionIndexes.Ri {
+		putFloat32(data, offsetCRIRi+i*separatedValueSize, v.Val)
+	}
+
+	putPackedFloat32s(data, offsetSpectralData5nm, m.SpectralData5nm[:])
+	putPackedFloat32s(data, offsetSpectralData1nm, m.SpectralData1nm[:])
+
+	putFloat32(data, offsetPPFD, m.PPFD.Val)
+
+	if size == MeasurementDataExtendedSize {
+		m.putExtended(data)
+	}
+
+	return data, nil
+}
+
+// putExtended writes extended data values. Missing values are left zeroed.
+func (m *Measurement) putExtended(data []byte) {
+	if m.TM30 != nil {
+		putFloat32(data, offsetTM30Rf, m.TM30.Rf.Val)
+		putFloat32(data, offsetTM30Rg, m.TM30.Rg.Val)
+		for i := range m.TM30.HueBinRf {
+			putFloat32(data, offsetTM30HueBinRf+i*separatedValueSize, m.TM30.HueBinRf[i].Val)
+			putFloat32(data, offsetTM30HueBinRcs+i*separatedValueSize, m.TM30.HueBinRcs[i].Val)
+			putFloat32(data, offsetTM30HueBinRhs+i*separatedValueSize, m.TM30.HueBinRhs[i].Val)
+		}
+		putPackedFloat32s(data, offsetTM30SampleRf, m.TM30.SampleRf[:])
+	}
+
+	if m.SSI != nil {
+		putFloat32(data, offsetSSITungsten, m.SSI.Tungsten.Val)
+		putFloat32(data, offsetSSIDaylight, m.SSI.Daylight.Val)
+	}
+
+	if m.TLCI != nil {
+		putFloat32(data, offsetTLCIQa, m.TLCI.Qa.Val)
+		putFloat32(data, offsetTLCITLMF, m.TLCI.TLMF.Val)
+	}
+}
+
+// putFloat32 writes a comma separator followed by float32 value at the given data offset.
+func putFloat32(data []byte, offset int, val float64) {
+	data[offset-1] = ','
+	binary.BigEndian.PutUint32(data[offset:offset+4], math.Float32bits(float32(val)))
+}
+
+// putFloat64 writes a comma separator followed by float64 value at the given data offset.
+func putFloat64(data []byte, offset int, val float64) {
+	data[offset-1] = ','
+	binary.BigEndian.PutUint64(data[offset:offset+8], math.Float64bits(val))
+}
+
+// putPackedFloat32s writes a comma separator followed by float32 values without separators
+// starting at the given data offset.
+func putPackedFloat32s(data []byte, offset int, vals []DecimalValue) {
+	data[offset-1] = ','
+	for i, v := range vals {
+		pos := offset + i*packedValueSize
+		binary.BigEndian.PutUint32(data[pos:pos+4], math.Float32bits(float32(v.Val)))
+	}
+}
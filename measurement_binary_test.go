@@ -0,0 +1,105 @@
+package skreader_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/akares/skreader"
+)
+
+func TestMeasurementMarshalBinary(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		testdata []byte
+		wantSize int
+	}{
+		{
+			name:     "range ok",
+			testdata: skreader.Testdata,
+			wantSize: skreader.MeasurementDataValidSize,
+		},
+		{
+			name:     "range under",
+			testdata: skreader.TestdataUnder,
+			wantSize: skreader.MeasurementDataValidSize,
+		},
+		{
+			name:     "extended",
+			testdata: testdataExtended(skreader.Testdata, 88),
+			wantSize: skreader.MeasurementDataExtendedSize,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := skreader.NewMeasurementFromBytes(tt.testdata)
+			if err != nil {
+				t.Fatalf("NewMeasurementFromBytes() error = %v", err)
+			}
+
+			data, err := m.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary() error = %v", err)
+			}
+			if len(data) != tt.wantSize {
+				t.Errorf("MarshalBinary() size = %d, want %d", len(data), tt.wantSize)
+			}
+
+			got, err := skreader.NewMeasurementFromBytes(data)
+			if err != nil {
+				t.Fatalf("NewMeasurementFromBytes(MarshalBinary()) error = %v", err)
+			}
+			if !reflect.DeepEqual(got, m) {
+				t.Errorf("NewMeasurementFromBytes(MarshalBinary()) = %s, want %s", got.Repr(), m.Repr())
+			}
+		})
+	}
+}
+
+func TestMeasurementMarshalBinaryHeader(t *testing.T) {
+	m, err := skreader.NewMeasurementFromBytes(skreader.Testdata)
+	if err != nil {
+		t.Fatalf("NewMeasurementFromBytes() error = %v", err)
+	}
+
+	data, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error = %v", err)
+	}
+
+	if got := string(data[:5]); got != "NRB@@" {
+		t.Errorf("MarshalBinary() prefix = %q, want %q", got, "NRB@@")
+	}
+
+	// Packet prefix and header up to the end of the second setting value and its separator.
+	const headerEnd = 48
+	if got, want := data[:headerEnd], skreader.Testdata[:headerEnd]; !reflect.DeepEqual(got, want) {
+		t.Errorf("MarshalBinary() header = %q, want %q", got, want)
+	}
+}
+
+func TestMeasurementMarshalBinarySynthesized(t *testing.T) {
+	m := &skreader.Measurement{} //nolint:exhaustruct
+	m.Illuminance.Lux.Val = 1000
+	m.ColorTemperature.Tcp.Val = 3200
+	for i := range m.SpectralData1nm {
+		m.SpectralData1nm[i].Val = float64(i) / 1000
+	}
+
+	data, err := m.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error = %v", err)
+	}
+
+	got, err := skreader.NewMeasurementFromBytes(data)
+	if err != nil {
+		t.Fatalf("NewMeasurementFromBytes() error = %v", err)
+	}
+	if got.Illuminance.Lux.Str != "1000" {
+		t.Errorf("Lux = %s, want %s", got.Illuminance.Lux.Str, "1000")
+	}
+	if got.ColorTemperature.Tcp.Str != "3200" {
+		t.Errorf("Tcp = %s, want %s", got.ColorTemperature.Tcp.Str, "3200")
+	}
+	if got.PeakWavelength != 780 {
+		t.Errorf("PeakWavelength = %d, want %d", got.PeakWavelength, 780)
+	}
+}
@@ -0,0 +1,154 @@
+package skreader
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnsupportedConfig is wrapped by errors of measurement configuration the connected device can't apply.
+var ErrUnsupportedConfig = errors.New("unsupported measurement configuration")
+
+// ConfigPolicy defines what to do with MeasurementConfig settings the connected device can't set remotely,
+// e.g. field of view on C-800 or anything on C-700.
+type ConfigPolicy int
+
+const (
+	// ConfigLenient skips settings device can't set remotely, device uses its own settings for them.
+	// Skipped settings are logged and reported by Measurement.Config.
+	ConfigLenient ConfigPolicy = iota
+	// ConfigStrict refuses to measure if any setting which differs from the default one can't be set remotely.
+	ConfigStrict
+)
+
+// AppliedMeasurementConfig describes measurement configuration actually sent to device.
+// Device uses its own settings (set with its buttons) instead of skipped ones.
+// Values device used for measurement are also available in Measurement.Header.
+type AppliedMeasurementConfig struct {
+	DeviceMeasurementConfig // requested configuration
+
+	MeasuringModeApplied bool
+	ShutterSpeedApplied  bool
+	FieldOfViewApplied   bool
+	ExposureTimeApplied  bool
+}
+
+// Skipped returns names of settings that were not applied.
+func (c AppliedMeasurementConfig) Skipped() []string {
+	var skipped []string
+	for _, s := range []struct {
+		name    string
+		applied bool
+	}{
+		{"measuring mode", c.MeasuringModeApplied},
+		{"shutter speed", c.ShutterSpeedApplied},
+		{"field of view", c.FieldOfViewApplied},
+		{"exposure time", c.ExposureTimeApplied},
+	} {
+		if !s.applied {
+			skipped = append(skipped, s.name)
+		}
+	}
+
+	return skipped
+}
+
+// DefaultMeasurementConfig returns measurement configuration with recommended values. It is used by NewDevice.
+func DefaultMeasurementConfig() DeviceMeasurementConfig {
+	return DeviceMeasurementConfig{
+		MeasuringMode: SkMeasuringModeAmbient,
+		FieldOfView:   SkFieldOfView2Deg,
+		ExposureTime:  SkExposureTimeAuto,
+		ShutterSpeed:  SkShutterSpeed125Sec,
+	}
+}
+
+// ValidateConfig checks measurement configuration against device capabilities and returns
+// the configuration which would be applied. Error wrapping ErrUnsupportedConfig is returned if any value
+// is not supported by device or if any setting which differs from the default one can't be set remotely.
+// Default values are not reported, so that default configuration is valid for all models.
+func (c DeviceCapabilities) ValidateConfig(config DeviceMeasurementConfig) (AppliedMeasurementConfig, error) {
+	applied, skipped, invalid := c.checkConfig(config)
+
+	problems := append(invalid, skipped...) //nolint:gocritic
+	if len(problems) > 0 {
+		return applied, c.configError(problems)
+	}
+
+	return applied, nil
+}
+
+// checkConfig returns configuration which would be applied, non-default settings which can't be set remotely
+// and values which are not supported by device.
+func (c DeviceCapabilities) checkConfig(config DeviceMeasurementConfig) (applied AppliedMeasurementConfig, skipped, invalid []string) {
+	def := DefaultMeasurementConfig()
+
+	applied = AppliedMeasurementConfig{
+		DeviceMeasurementConfig: config,
+		MeasuringModeApplied:    c.MeasurementConfiguration,
+		ShutterSpeedApplied:     c.MeasurementConfiguration,
+		FieldOfViewApplied:      c.ExtendedMeasurementConfiguration,
+		ExposureTimeApplied:     c.ExtendedMeasurementConfiguration,
+	}
+
+	if !c.SupportsMeasuringMode(config.MeasuringMode) {
+		invalid = append(invalid, fmt.Sprintf("measuring mode %s is not supported", config.MeasuringMode))
+	}
+
+	if !c.MeasurementConfiguration {
+		if config.MeasuringMode != def.MeasuringMode {
+			skipped = append(skipped, fmt.Sprintf("measuring mode %s can't be set remotely", config.MeasuringMode))
+		}
+		if config.ShutterSpeed != def.ShutterSpeed {
+			skipped = append(skipped, fmt.Sprintf("shutter speed %s can't be set remotely", config.ShutterSpeed))
+		}
+	}
+
+	if c.ExtendedMeasurementConfiguration {
+		if !containsFieldOfView(c.FieldsOfView, config.FieldOfView) {
+			invalid = append(invalid, fmt.Sprintf("field of view %s is not supported", config.FieldOfView))
+		}
+		if !containsExposureTime(c.ExposureTimes, config.ExposureTime) {
+			invalid = append(invalid, fmt.Sprintf("exposure time %s is not supported", config.ExposureTime))
+		}
+	} else {
+		if config.FieldOfView != def.FieldOfView {
+			skipped = append(skipped, fmt.Sprintf("field of view %s can't be set remotely", config.FieldOfView))
+		}
+		if config.ExposureTime != def.ExposureTime {
+			skipped = append(skipped, fmt.Sprintf("exposure time %s can't be set remotely", config.ExposureTime))
+		}
+	}
+
+	return applied, skipped, invalid
+}
+
+func (c DeviceCapabilities) configError(problems []string) error {
+	return fmt.Errorf("%w: %s by %s firmware %d", ErrUnsupportedConfig, strings.Join(problems, ", "), c.Model, c.Firmware)
+}
+
+// ValidateMeasurementConfig checks MeasurementConfig against connected device capabilities,
+// see DeviceCapabilities.ValidateConfig.
+func (d *Device) ValidateMeasurementConfig() (AppliedMeasurementConfig, error) {
+	return d.Capabilities().ValidateConfig(d.MeasurementConfig)
+}
+
+func containsFieldOfView(list []SkFieldOfView, fov SkFieldOfView) bool {
+	for _, f := range list {
+		if f == fov {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsExposureTime(list []SkExposureTime, exp SkExposureTime) bool {
+	for _, e := range list {
+		if e == exp {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,93 @@
+package skreader_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/akares/skreader"
+)
+
+func TestValidateConfig(t *testing.T) {
+	def := skreader.DefaultMeasurementConfig()
+	fov10 := def
+	fov10.FieldOfView = skreader.SkFieldOfView10Deg
+	flash := def
+	flash.MeasuringMode = skreader.SkMeasuringModeCordlessFlash
+	badExposure := def
+	badExposure.ExposureTime = skreader.SkExposureTime(5)
+
+	for _, tt := range []struct {
+		name     string
+		caps     skreader.DeviceCapabilities
+		config   skreader.DeviceMeasurementConfig
+		wantErr  bool
+		extended bool // whether field of view and exposure time are applied
+	}{
+		{name: "C-7000 default", caps: skreader.NewDeviceCapabilities("C-7000", 26), config: def, wantErr: false, extended: true},
+		{name: "C-7000 10°", caps: skreader.NewDeviceCapabilities("C-7000", 26), config: fov10, wantErr: false, extended: true},
+		{name: "C-7000 bad exposure", caps: skreader.NewDeviceCapabilities("C-7000", 26), config: badExposure, wantErr: true, extended: true},
+		{name: "C-7000 old firmware default", caps: skreader.NewDeviceCapabilities("C-7000", 25), config: def, wantErr: false, extended: false},
+		{name: "C-7000 old firmware 10°", caps: skreader.NewDeviceCapabilities("C-7000", 25), config: fov10, wantErr: true, extended: false},
+		{name: "C-800 10°", caps: skreader.NewDeviceCapabilities("C-800", 12), config: fov10, wantErr: true, extended: false},
+		{name: "C-800 flash", caps: skreader.NewDeviceCapabilities("C-800", 12), config: flash, wantErr: false, extended: false},
+		{name: "C-700 default", caps: skreader.NewDeviceCapabilities("C-700", 10), config: def, wantErr: false, extended: false},
+		{name: "C-700 flash", caps: skreader.NewDeviceCapabilities("C-700", 10), config: flash, wantErr: true, extended: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			applied, err := tt.caps.ValidateConfig(tt.config)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, skreader.ErrUnsupportedConfig) {
+				t.Errorf("ValidateConfig() error = %v, want %v", err, skreader.ErrUnsupportedConfig)
+			}
+			assert.Equal(t, tt.config, applied.DeviceMeasurementConfig, "applied config invalid")
+			assert.Equal(t, tt.caps.MeasurementConfiguration, applied.MeasuringModeApplied, "MeasuringModeApplied invalid")
+			assert.Equal(t, tt.extended, applied.FieldOfViewApplied, "FieldOfViewApplied invalid")
+			assert.Equal(t, tt.extended, applied.ExposureTimeApplied, "ExposureTimeApplied invalid")
+		})
+	}
+}
+
+func TestConfigPolicy(t *testing.T) {
+	fov10 := skreader.DefaultMeasurementConfig()
+	fov10.FieldOfView = skreader.SkFieldOfView10Deg
+	setFov := fmt.Sprintf("%s,%d", skreader.SkCommandSetFov, skreader.SkFieldOfView10Deg)
+
+	// C-800 can't set field of view remotely.
+	sim := skreader.NewSimulatedDevice("C-800")
+
+	d, err := skreader.NewDevice(sim, skreader.WithConfig(fov10), skreader.WithConfigPolicy(skreader.ConfigStrict))
+	if err != nil {
+		t.Fatalf("NewDevice() error = %v", err)
+	}
+	defer d.Close()
+
+	if _, err = d.Measure(); !errors.Is(err, skreader.ErrUnsupportedConfig) {
+		t.Fatalf("strict Measure() error = %v, want %v", err, skreader.ErrUnsupportedConfig)
+	}
+	st, err := d.State()
+	if err != nil {
+		t.Fatalf("State() error = %v", err)
+	}
+	assert.Equal(t, skreader.SkRemoteStatusOff, st.Remote, "remote status after rejected config invalid")
+
+	counter := &commandCounter{UsbAdapter: sim, writes: map[string]int{}}
+	d, err = skreader.NewDevice(counter, skreader.WithConfig(fov10))
+	if err != nil {
+		t.Fatalf("NewDevice() error = %v", err)
+	}
+	defer d.Close()
+
+	m, err := d.Measure()
+	if err != nil {
+		t.Fatalf("lenient Measure() error = %v", err)
+	}
+	if assert.NotNil(t, m.Config, "Measure() Config invalid") {
+		assert.Equal(t, []string{"field of view", "exposure time"}, m.Config.Skipped(), "Measure() skipped settings invalid")
+	}
+	assert.Equal(t, 0, counter.writes[setFov], "AG writes invalid")
+}
@@ -0,0 +1,156 @@
+package skreader
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// MeasurementDecoderFunc decodes raw measurement result data. Measuring mode is the one the data was
+// measured in, it is used to check illuminance limits (see NewFlashMeasurementFromBytes).
+type MeasurementDecoderFunc func(data []byte, mode SkMeasuringMode) (*Measurement, error)
+
+// MeasurementDecoder describes one layout of measurement result data packet and the models,
+// firmware versions and packet sizes it is used for.
+type MeasurementDecoder struct {
+	Name        string   // layout name, e.g. "C-7000 extended"
+	Models      []string // models using the layout (regional variants match too), empty for all models
+	MinFirmware int      // minimal main firmware version, 0 for all versions
+	MinSize     int      // minimal packet size in bytes
+	MaxSize     int      // maximal packet size in bytes, 0 for no limit
+	Decode      MeasurementDecoderFunc
+}
+
+// Matches reports whether decoder is used for packet of given size sent by given model and firmware.
+func (d *MeasurementDecoder) Matches(model string, firmware, size int) bool {
+	if size < d.MinSize || (d.MaxSize > 0 && size > d.MaxSize) {
+		return false
+	}
+	if firmware < d.MinFirmware {
+		return false
+	}
+	if len(d.Models) == 0 {
+		return true
+	}
+	for _, name := range d.Models {
+		if model == name || strings.HasPrefix(model, name+"-") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MeasurementDecoderRegistry selects measurement data decoder by device model, firmware and packet size.
+// It is safe for concurrent use.
+type MeasurementDecoderRegistry struct {
+	decoders []MeasurementDecoder
+	mu       sync.RWMutex
+}
+
+// NewMeasurementDecoderRegistry creates registry with given decoders.
+// Decoders are registered in the given order, see Register.
+func NewMeasurementDecoderRegistry(decoders ...MeasurementDecoder) *MeasurementDecoderRegistry {
+	r := &MeasurementDecoderRegistry{} //nolint:exhaustruct
+	for _, d := range decoders {
+		r.Register(d)
+	}
+
+	return r
+}
+
+// Register adds decoder to the registry. Decoders registered later take precedence,
+// so new layouts can be added for specific models and firmware versions without replacing existing ones.
+func (r *MeasurementDecoderRegistry) Register(d MeasurementDecoder) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.decoders = append(r.decoders, d)
+}
+
+// Find returns decoder of packet of given size sent by given model and firmware, or false if layout is not known.
+func (r *MeasurementDecoderRegistry) Find(model string, firmware, size int) (MeasurementDecoder, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for i := len(r.decoders) - 1; i >= 0; i-- {
+		if r.decoders[i].Matches(model, firmware, size) {
+			return r.decoders[i], true
+		}
+	}
+
+	return MeasurementDecoder{}, false //nolint:exhaustruct
+}
+
+// Decode decodes measurement data sent by device with given capabilities using matching decoder.
+// If layout is not known, *MeasurementLayoutError is returned.
+func (r *MeasurementDecoderRegistry) Decode(caps DeviceCapabilities, data []byte, mode SkMeasuringMode) (*Measurement, error) {
+	d, ok := r.Find(caps.Model, caps.Firmware, len(data))
+	if !ok {
+		return nil, &MeasurementLayoutError{Model: caps.Model, Firmware: caps.Firmware, Data: data}
+	}
+
+	m, err := d.Decode(data, mode)
+	if err != nil {
+		return nil, fmt.Errorf("%s measurement data: %w", d.Name, err)
+	}
+
+	return m, nil
+}
+
+// Built-in measurement data layouts.
+var (
+	// BaseMeasurementDecoder decodes data layout tested on C-7000, C-800, C-700.
+	// Data following the base data is ignored.
+	BaseMeasurementDecoder = MeasurementDecoder{
+		Name:        "base",
+		Models:      nil,
+		MinFirmware: 0,
+		MinSize:     MeasurementDataValidSize,
+		MaxSize:     0,
+		Decode: func(data []byte, mode SkMeasuringMode) (*Measurement, error) {
+			return decodeMeasurement(data, illuminanceLimitsOf(mode), false)
+		},
+	}
+
+	// ExtendedMeasurementDecoder decodes C-7000 FW > 25 data layout: base data followed by extended data.
+	ExtendedMeasurementDecoder = MeasurementDecoder{
+		Name:        "C-7000 extended",
+		Models:      []string{"C-7000"},
+		MinFirmware: modelProfiles["C-7000"].extendedMinFirmware,
+		MinSize:     MeasurementDataExtendedSize,
+		MaxSize:     0,
+		Decode: func(data []byte, mode SkMeasuringMode) (*Measurement, error) {
+			return decodeMeasurement(data, illuminanceLimitsOf(mode), true)
+		},
+	}
+)
+
+// DefaultMeasurementDecoders is the registry used by Device to decode measurement results.
+// Register decoders of new layouts here.
+var DefaultMeasurementDecoders = NewMeasurementDecoderRegistry(
+	BaseMeasurementDecoder,
+	ExtendedMeasurementDecoder,
+)
+
+// MeasurementLayoutError is returned when there is no decoder for the measurement data sent by device.
+// It wraps ErrUnknownMeasurementLayout. Raw data is kept to be attached to bug reports, see HexDump.
+type MeasurementLayoutError struct {
+	Model    string
+	Firmware int
+	Data     []byte // raw measurement data
+}
+
+func (e *MeasurementLayoutError) Error() string {
+	return fmt.Sprintf("%s: %d bytes from %s FW %d", ErrUnknownMeasurementLayout, len(e.Data), e.Model, e.Firmware)
+}
+
+func (e *MeasurementLayoutError) Unwrap() error {
+	return ErrUnknownMeasurementLayout
+}
+
+// HexDump returns raw measurement data in hexdump -C format.
+func (e *MeasurementLayoutError) HexDump() string {
+	return hex.Dump(e.Data)
+}
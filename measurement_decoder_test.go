@@ -0,0 +1,94 @@
+package skreader_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/akares/skreader"
+)
+
+func TestMeasurementDecoderRegistryFind(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		model    string
+		firmware int
+		size     int
+		want     string
+		wantOk   bool
+	}{
+		{name: "C-700", model: "C-700", firmware: 11, size: 2380, want: "base", wantOk: true},
+		{name: "C-800 longer data", model: "C-800", firmware: 27, size: 3047, want: "base", wantOk: true},
+		{name: "C-7000 old firmware", model: "C-7000", firmware: 25, size: 3047, want: "base", wantOk: true},
+		{name: "C-7000 extended", model: "C-7000", firmware: 26, size: 3047, want: "C-7000 extended", wantOk: true},
+		{name: "C-7000 regional variant", model: "C-7000-U", firmware: 27, size: 3047, want: "C-7000 extended", wantOk: true},
+		{name: "C-7000 base data", model: "C-7000", firmware: 27, size: 2380, want: "base", wantOk: true},
+		{name: "too short", model: "C-7000", firmware: 27, size: 100, want: "", wantOk: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			d, ok := skreader.DefaultMeasurementDecoders.Find(tt.model, tt.firmware, tt.size)
+			if ok != tt.wantOk || d.Name != tt.want {
+				t.Errorf("Find() = %q, %v, want %q, %v", d.Name, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestMeasurementDecoderRegistryRegister(t *testing.T) {
+	var decoded []byte
+	custom := skreader.MeasurementDecoder{
+		Name:        "C-9000",
+		Models:      []string{"C-9000"},
+		MinFirmware: 0,
+		MinSize:     10,
+		MaxSize:     20,
+		Decode: func(data []byte, _ skreader.SkMeasuringMode) (*skreader.Measurement, error) {
+			decoded = data
+
+			return &skreader.Measurement{}, nil //nolint:exhaustruct
+		},
+	}
+
+	r := skreader.NewMeasurementDecoderRegistry(skreader.BaseMeasurementDecoder, skreader.ExtendedMeasurementDecoder)
+	r.Register(custom)
+
+	caps := skreader.NewDeviceCapabilities("C-9000", 1)
+	if _, err := r.Decode(caps, make([]byte, 15), skreader.SkMeasuringModeAmbient); err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if len(decoded) != 15 {
+		t.Errorf("decoded %d bytes, want %d", len(decoded), 15)
+	}
+
+	// Other models still use built-in decoders.
+	if d, _ := r.Find("C-800", 27, 15); d.Name == custom.Name {
+		t.Errorf("Find() = %q for other model", d.Name)
+	}
+	if d, _ := r.Find("C-9000", 1, skreader.MeasurementDataValidSize); d.Name != "base" {
+		t.Errorf("Find() = %q, want %q for size out of custom range", d.Name, "base")
+	}
+}
+
+func TestMeasurementDecoderRegistryUnknownLayout(t *testing.T) {
+	data := skreader.Testdata[:100]
+	caps := skreader.NewDeviceCapabilities("C-7000", 27)
+
+	_, err := skreader.DefaultMeasurementDecoders.Decode(caps, data, skreader.SkMeasuringModeAmbient)
+	if !errors.Is(err, skreader.ErrUnknownMeasurementLayout) {
+		t.Fatalf("Decode() error = %v, want %v", err, skreader.ErrUnknownMeasurementLayout)
+	}
+
+	var layoutErr *skreader.MeasurementLayoutError
+	if !errors.As(err, &layoutErr) {
+		t.Fatalf("Decode() error = %T, want *MeasurementLayoutError", err)
+	}
+	if layoutErr.Model != "C-7000" || layoutErr.Firmware != 27 || len(layoutErr.Data) != len(data) {
+		t.Errorf("MeasurementLayoutError = %+v", layoutErr)
+	}
+	if !strings.Contains(err.Error(), "100 bytes from C-7000 FW 27") {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	if !strings.HasPrefix(layoutErr.HexDump(), "00000000  4e 52 42 40 40") {
+		t.Errorf("HexDump() = %q", layoutErr.HexDump())
+	}
+}
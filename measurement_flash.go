@@ -0,0 +1,77 @@
+package skreader
+
+import "fmt"
+
+// FlashMeasurement represents a flash measurement data from SEKONIC device.
+// Flash measurement result uses the same data packet layout as ambient one, but illuminance
+// values are integrated over the flash duration, so they are represented by distinct type.
+type FlashMeasurement struct {
+	Header MeasurementHeader // Measurement title and settings used by device
+
+	Illuminance      FlashIlluminanceValue   // Illuminance integrated over the flash duration
+	Tristimulus      TristimulusValue        // Tristimulus values in XYZ color space
+	ColorTemperature ColorTemperatureValue   // Correlated Color Temperature
+	CIE1931          CIE1931Value            // CIE 1931 (x, y, z) chromaticity coordinates
+	CIE1976          CIE1976Value            // CIE 1976 (u', v') chromaticity coordinates
+	DWL              DominantWavelengthValue // Dominant Wavelength
+	PPFD             DecimalValue            // Photosynthetic Photon Flux Density integrated over the flash duration
+
+	ColorRenditionIndexes ColorRenditionIndexesValue // Color Rendition Indexes
+
+	SpectralData5nm [81]DecimalValue  // Spectral Data (5nm)
+	SpectralData1nm [401]DecimalValue // Spectral Data (1nm)
+	PeakWavelength  int               // Peak Wavelength (380...780nm)
+
+	TM30 *TM30Value // ANSI/IES TM-30 color rendition (extended data only, nil if not available)
+	SSI  *SSIValue  // Spectral Similarity Index (extended data only, nil if not available)
+	TLCI *TLCIValue // Television Lighting Consistency Index (extended data only, nil if not available)
+
+	Config *AppliedMeasurementConfig // configuration applied by MeasureFlash or Session, nil if measured otherwise
+}
+
+// FlashIlluminanceValue represents a flash illuminance value in Lux-second and foot-candle-second units.
+type FlashIlluminanceValue struct {
+	LuxSecond        DecimalValue
+	FootCandleSecond DecimalValue
+}
+
+// NewFlashMeasurementFromBytes creates a new FlashMeasurement instance from the given raw
+// binary response from SEKONIC device measured in cordless or cord flash measuring mode.
+func NewFlashMeasurementFromBytes(data []byte) (*FlashMeasurement, error) {
+	m, err := parseMeasurement(data, flashIlluminanceLimits)
+	if err != nil {
+		return nil, err
+	}
+
+	return newFlashMeasurement(m), nil
+}
+
+// newFlashMeasurement converts measurement decoded using flash illuminance limits to FlashMeasurement.
+func newFlashMeasurement(m *Measurement) *FlashMeasurement {
+	return &FlashMeasurement{
+		Header: m.Header,
+		Illuminance: FlashIlluminanceValue{
+			LuxSecond:        m.Illuminance.Lux,
+			FootCandleSecond: m.Illuminance.FootCandle,
+		},
+		Tristimulus:           m.Tristimulus,
+		ColorTemperature:      m.ColorTemperature,
+		CIE1931:               m.CIE1931,
+		CIE1976:               m.CIE1976,
+		DWL:                   m.DWL,
+		PPFD:                  m.PPFD,
+		ColorRenditionIndexes: m.ColorRenditionIndexes,
+		SpectralData5nm:       m.SpectralData5nm,
+		SpectralData1nm:       m.SpectralData1nm,
+		PeakWavelength:        m.PeakWavelength,
+		TM30:                  m.TM30,
+		SSI:                   m.SSI,
+		TLCI:                  m.TLCI,
+	}
+}
+
+// String returns limited string representation of the FlashMeasurement instance.
+// Used mostly for debugging.
+func (m *FlashMeasurement) String() string {
+	return fmt.Sprintf("Lux*s=%s x=%s y=%s CCT=%s", m.Illuminance.LuxSecond.Str, m.CIE1931.X.Str, m.CIE1931.Y.Str, m.ColorTemperature.Tcp.Str)
+}
@@ -0,0 +1,82 @@
+package skreader_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/akares/skreader"
+)
+
+func TestFlashMeasurement(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		testdata  []byte
+		wantRange skreader.ValueRange
+		wantErr   bool
+	}{
+		{
+			name:      "range ok",
+			testdata:  skreader.Testdata,
+			wantRange: skreader.RangeOk,
+			wantErr:   false,
+		},
+		{
+			name:      "range under",
+			testdata:  skreader.TestdataUnder,
+			wantRange: skreader.RangeUnder,
+			wantErr:   false,
+		},
+		{
+			name:     "invalid size",
+			testdata: skreader.Testdata[:100],
+			wantErr:  true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := skreader.NewFlashMeasurementFromBytes(tt.testdata)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if f.Illuminance.LuxSecond.Range != tt.wantRange {
+				t.Errorf("got %v, want %v", f.Illuminance.LuxSecond.Range, tt.wantRange)
+			}
+
+			m, _ := skreader.NewMeasurementFromBytes(tt.testdata)
+			if f.Illuminance.LuxSecond.Val != m.Illuminance.Lux.Val {
+				t.Errorf("LuxSecond = %v, want %v", f.Illuminance.LuxSecond.Val, m.Illuminance.Lux.Val)
+			}
+			if f.PPFD != m.PPFD {
+				t.Errorf("PPFD = %v, want %v", f.PPFD, m.PPFD)
+			}
+			if f.CIE1931 != m.CIE1931 {
+				t.Errorf("CIE1931 = %v, want %v", f.CIE1931, m.CIE1931)
+			}
+		})
+	}
+}
+
+func TestFlashMeasurementExtended(t *testing.T) {
+	data := testdataExtended(skreader.Testdata, 88)
+
+	f, err := skreader.NewFlashMeasurementFromBytes(data)
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	if f.TM30 == nil || f.SSI == nil || f.TLCI == nil {
+		t.Fatalf("TM30 = %v, SSI = %v, TLCI = %v, want all set", f.TM30, f.SSI, f.TLCI)
+	}
+
+	m, _ := skreader.NewMeasurementFromBytes(data)
+	if !reflect.DeepEqual(f.TM30, m.TM30) {
+		t.Errorf("TM30 = %v, want %v", f.TM30, m.TM30)
+	}
+	if !reflect.DeepEqual(f.SSI, m.SSI) {
+		t.Errorf("SSI = %v, want %v", f.SSI, m.SSI)
+	}
+	if !reflect.DeepEqual(f.TLCI, m.TLCI) {
+		t.Errorf("TLCI = %v, want %v", f.TLCI, m.TLCI)
+	}
+}
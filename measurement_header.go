@@ -0,0 +1,125 @@
+package skreader
+
+import "fmt"
+
+// MeasurementHeader represents the measurement title and settings which SEKONIC device reports
+// at the beginning of the measurement result data. Unlike DeviceMeasurementConfig, these are
+// the settings the device actually used for the measurement.
+type MeasurementHeader struct {
+	MeasuringMode SkMeasuringMode
+	Title         string // measurement title as set on the device, e.g. "Untitled"
+	MemoryNumber  int    // number of the device memory slot the measurement is stored to
+	FieldOfView   SkFieldOfView
+	ExposureTime  SkExposureTime
+
+	Settings [2]MeasurementHeaderSetting // other settings, meaning depends on model and measuring mode
+}
+
+// MeasurementHeaderSetting represents one of the other settings reported in the measurement header
+// as a setting code followed by its value.
+type MeasurementHeaderSetting struct {
+	Code  int
+	Value float64
+}
+
+// Measurement data packet header layout. Numbers are ASCII decimal digits of fixed width,
+// every field is followed by comma. Setting values are big-endian float32 numbers.
+const (
+	offsetHeaderMeasuringMode = 5
+	offsetHeaderTitle         = 7
+	offsetHeaderMemoryNumber  = 24
+	offsetHeaderFieldOfView   = 29
+	offsetHeaderExposureTime  = 31
+	offsetHeaderSetting1Code  = 34
+	offsetHeaderSetting1Value = 36
+	offsetHeaderSetting2Code  = 41
+	offsetHeaderSetting2Value = 43
+
+	headerMeasuringModeSize = 1
+	headerTitleSize         = 16
+	headerMemoryNumberSize  = 4
+	headerFieldOfViewSize   = 1
+	headerExposureTimeSize  = 2
+	headerSettingCodeSize   = 1
+	headerSettingValueSize  = 4
+)
+
+// headerSettingOffsets contains code and value offsets of MeasurementHeader.Settings.
+var headerSettingOffsets = [2][2]int{
+	{offsetHeaderSetting1Code, offsetHeaderSetting1Value},
+	{offsetHeaderSetting2Code, offsetHeaderSetting2Value},
+}
+
+// parseHeader parses the measurement header. Data size must be checked by the caller.
+// Numbers which can't be parsed are left zeroed, the header is informational only
+// and it must not make the measurement itself unusable.
+func parseHeader(data []byte) MeasurementHeader {
+	h := MeasurementHeader{
+		MeasuringMode: SkMeasuringMode(parseHeaderInt(data, offsetHeaderMeasuringMode, headerMeasuringModeSize)),
+		Title:         toString(data[offsetHeaderTitle : offsetHeaderTitle+headerTitleSize]),
+		MemoryNumber:  parseHeaderInt(data, offsetHeaderMemoryNumber, headerMemoryNumberSize),
+		FieldOfView:   SkFieldOfView(parseHeaderInt(data, offsetHeaderFieldOfView, headerFieldOfViewSize)),
+		ExposureTime:  SkExposureTime(parseHeaderInt(data, offsetHeaderExposureTime, headerExposureTimeSize)),
+		Settings:      [2]MeasurementHeaderSetting{},
+	}
+
+	for i, offsets := range headerSettingOffsets {
+		h.Settings[i] = MeasurementHeaderSetting{
+			Code:  parseHeaderInt(data, offsets[0], headerSettingCodeSize),
+			Value: parseFloat32(data, offsets[1]),
+		}
+	}
+
+	return h
+}
+
+// putHeader writes the measurement header in the same layout as parseHeader reads it.
+func (h *MeasurementHeader) putHeader(data []byte) {
+	putHeaderInt(data, offsetHeaderMeasuringMode, headerMeasuringModeSize, int(h.MeasuringMode))
+
+	title := data[offsetHeaderTitle : offsetHeaderTitle+headerTitleSize]
+	for i := range title {
+		title[i] = 0
+	}
+	copy(title, h.Title)
+	data[offsetHeaderTitle+headerTitleSize] = ','
+
+	putHeaderInt(data, offsetHeaderMemoryNumber, headerMemoryNumberSize, h.MemoryNumber)
+	putHeaderInt(data, offsetHeaderFieldOfView, headerFieldOfViewSize, int(h.FieldOfView))
+	putHeaderInt(data, offsetHeaderExposureTime, headerExposureTimeSize, int(h.ExposureTime))
+
+	for i, offsets := range headerSettingOffsets {
+		putHeaderInt(data, offsets[0], headerSettingCodeSize, h.Settings[i].Code)
+		putFloat32(data, offsets[1], h.Settings[i].Value)
+		data[offsets[1]+headerSettingValueSize] = ','
+	}
+}
+
+// String returns human readable representation of the measurement header.
+func (h MeasurementHeader) String() string {
+	return fmt.Sprintf("%s #%04d: %s mode, %s field of view, %s exposure time",
+		h.Title, h.MemoryNumber, h.MeasuringMode, h.FieldOfView, h.ExposureTime)
+}
+
+// parseHeaderInt parses fixed width decimal number at the given data offset or returns 0 if it is not a number.
+func parseHeaderInt(data []byte, offset, size int) int {
+	val, err := toInt(data[offset : offset+size])
+	if err != nil {
+		return 0
+	}
+
+	return val
+}
+
+// putHeaderInt writes fixed width decimal number followed by comma separator at the given data offset.
+// Numbers which don't fit the width are truncated to the lowest digits.
+func putHeaderInt(data []byte, offset, size, val int) {
+	s := fmt.Sprintf("%0*d", size, val)
+	if len(s) > size {
+		s = s[len(s)-size:]
+	}
+
+	copy(data[offset:offset+size], s)
+	data[offset+size] = ','
+
+}
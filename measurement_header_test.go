@@ -0,0 +1,90 @@
+package skreader_test
+
+import (
+	"testing"
+
+	"github.com/akares/skreader"
+)
+
+func TestMeasurementHeader(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		testdata []byte
+		want     skreader.MeasurementHeader
+	}{
+		{
+			name:     "range ok",
+			testdata: skreader.Testdata,
+			want: skreader.MeasurementHeader{
+				MeasuringMode: skreader.SkMeasuringModeAmbient,
+				Title:         "Untitled",
+				MemoryNumber:  3,
+				FieldOfView:   skreader.SkFieldOfView2Deg,
+				ExposureTime:  skreader.SkExposureTimeAuto,
+				Settings: [2]skreader.MeasurementHeaderSetting{
+					{Code: 4, Value: 25},
+					{Code: 3, Value: 12.5},
+				},
+			},
+		},
+		{
+			name:     "range under",
+			testdata: skreader.TestdataUnder,
+			want: skreader.MeasurementHeader{
+				MeasuringMode: skreader.SkMeasuringModeAmbient,
+				Title:         "Untitled",
+				MemoryNumber:  3,
+				FieldOfView:   skreader.SkFieldOfView10Deg,
+				ExposureTime:  skreader.SkExposureTimeAuto,
+				Settings: [2]skreader.MeasurementHeaderSetting{
+					{Code: 4, Value: 25},
+					{Code: 3, Value: 12.5},
+				},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := skreader.NewMeasurementFromBytes(tt.testdata)
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if m.Header != tt.want {
+				t.Errorf("Header = %+v, want %+v", m.Header, tt.want)
+			}
+
+			f, err := skreader.NewFlashMeasurementFromBytes(tt.testdata)
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if f.Header != tt.want {
+				t.Errorf("flash Header = %+v, want %+v", f.Header, tt.want)
+			}
+		})
+	}
+}
+
+func TestMeasurementHeaderInvalidNumbers(t *testing.T) {
+	data := make([]byte, len(skreader.Testdata))
+	copy(data, skreader.Testdata)
+	copy(data[24:28], "x0y3")
+
+	m, err := skreader.NewMeasurementFromBytes(data)
+	if err != nil {
+		t.Fatalf("error = %v, want header errors to be ignored", err)
+	}
+	if m.Header.MemoryNumber != 0 || m.Header.Title != "Untitled" {
+		t.Errorf("Header = %+v, want zero memory number", m.Header)
+	}
+}
+
+func TestMeasurementHeaderString(t *testing.T) {
+	m, err := skreader.NewMeasurementFromBytes(skreader.Testdata)
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+
+	want := "Untitled #0003: ambient mode, 2° field of view, auto exposure time"
+	if got := m.Header.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
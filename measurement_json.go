@@ -7,17 +7,45 @@ import "time"
 // MeasurementJSON is a struct that represents a JSON object that can be used to
 // serialize a Measurement object.
 type MeasurementJSON struct {
-	Name             string               `json:"Name"`
-	Note             string               `json:"Note"`
-	Timestamp        int64                `json:"Timestamp"`
-	Illuminance      IlluminanceJSON      `json:"Illuminance"`
-	ColorTemperature ColorTemperatureJSON `json:"ColorTemperature"`
-	Tristimulus      TristimulusJSON      `json:"Tristimulus"`
-	CIE1931          CIE1931JSON          `json:"CIE1931"`
-	CIE1976          CIE1976JSON          `json:"CIE1976"`
-	DWL              DWLJSON              `json:"DWL"`
-	CRI              CRIJSON              `json:"CRI"`
-	SpectralData     []SpectralDataJSON   `json:"SpectralData"`
+	Name             string                `json:"Name"`
+	Note             string                `json:"Note"`
+	Timestamp        int64                 `json:"Timestamp"`
+	Header           MeasurementHeaderJSON `json:"Header"`
+	Illuminance      IlluminanceJSON       `json:"Illuminance"`
+	ColorTemperature ColorTemperatureJSON  `json:"ColorTemperature"`
+	Tristimulus      TristimulusJSON       `json:"Tristimulus"`
+	CIE1931          CIE1931JSON           `json:"CIE1931"`
+	CIE1976          CIE1976JSON           `json:"CIE1976"`
+	DWL              DWLJSON               `json:"DWL"`
+	CRI              CRIJSON               `json:"CRI"`
+	TM30             *TM30JSON             `json:"TM30,omitempty"`
+	SSI              *SSIJSON              `json:"SSI,omitempty"`
+	TLCI             *TLCIJSON             `json:"TLCI,omitempty"`
+	SpectralData     []SpectralDataJSON    `json:"SpectralData"`
+	AppliedConfig    *AppliedConfigJSON    `json:"AppliedConfig,omitempty"`
+}
+
+// AppliedConfigJSON lists configuration settings applied to device, skipped ones are empty.
+type AppliedConfigJSON struct {
+	MeasuringMode string   `json:"MeasuringMode,omitempty"`
+	ShutterSpeed  string   `json:"ShutterSpeed,omitempty"`
+	FieldOfView   string   `json:"FieldOfView,omitempty"`
+	ExposureTime  string   `json:"ExposureTime,omitempty"`
+	Skipped       []string `json:"Skipped"`
+}
+
+type MeasurementHeaderJSON struct {
+	Title         string                         `json:"Title"`
+	MemoryNumber  int                            `json:"MemoryNumber"`
+	MeasuringMode string                         `json:"MeasuringMode"`
+	FieldOfView   string                         `json:"FieldOfView"`
+	ExposureTime  string                         `json:"ExposureTime"`
+	Settings      []MeasurementHeaderSettingJSON `json:"Settings"`
+}
+
+type MeasurementHeaderSettingJSON struct {
+	Code  int     `json:"Code"`
+	Value float64 `json:"Value"`
 }
 
 type IlluminanceJSON struct {
@@ -56,6 +84,25 @@ type CRIJSON struct {
 	Ri []float64 `json:"Ri"`
 }
 
+type TM30JSON struct {
+	Rf        float64   `json:"Rf"`
+	Rg        float64   `json:"Rg"`
+	HueBinRf  []float64 `json:"HueBinRf"`
+	HueBinRcs []float64 `json:"HueBinRcs"`
+	HueBinRhs []float64 `json:"HueBinRhs"`
+	SampleRf  []float64 `json:"SampleRf"`
+}
+
+type SSIJSON struct {
+	Tungsten float64 `json:"Tungsten"`
+	Daylight float64 `json:"Daylight"`
+}
+
+type TLCIJSON struct {
+	Qa   float64 `json:"Qa"`
+	TLMF float64 `json:"TLMF"`
+}
+
 type SpectralDataJSON struct {
 	Range  SpectralDataRangeJSON `json:"Range"`
 	Values []float64             `json:"Values"`
@@ -73,6 +120,7 @@ func NewJSONMeasurement(meas *Measurement, measName, measNote string, measTime t
 		Name:      measName,
 		Note:      measNote,
 		Timestamp: measTime.Unix(),
+		Header:    newMeasurementHeaderJSON(meas.Header),
 		Illuminance: IlluminanceJSON{
 			LUX: meas.Illuminance.Lux.Val,
 			Fc:  meas.Illuminance.FootCandle.Val,
@@ -110,6 +158,23 @@ func NewJSONMeasurement(meas *Measurement, measName, measNote string, measTime t
 		res.CRI.Ri[i] = val.Val
 	}
 
+	// Populate TM-30, SSI and TLCI (extended data only)
+	if meas.TM30 != nil {
+		res.TM30 = newTM30JSON(meas.TM30)
+	}
+	if meas.SSI != nil {
+		res.SSI = &SSIJSON{
+			Tungsten: meas.SSI.Tungsten.Val,
+			Daylight: meas.SSI.Daylight.Val,
+		}
+	}
+	if meas.TLCI != nil {
+		res.TLCI = &TLCIJSON{
+			Qa:   meas.TLCI.Qa.Val,
+			TLMF: meas.TLCI.TLMF.Val,
+		}
+	}
+
 	// Populate 1nm
 	spectralData1nm := SpectralDataJSON{
 		Range: SpectralDataRangeJSON{
@@ -140,5 +205,151 @@ func NewJSONMeasurement(meas *Measurement, measName, measNote string, measTime t
 
 	res.SpectralData = []SpectralDataJSON{spectralData1nm, spectralData5nm}
 
+	if meas.Config != nil {
+		res.AppliedConfig = newAppliedConfigJSON(meas.Config)
+	}
+
+	return res
+}
+
+func newAppliedConfigJSON(c *AppliedMeasurementConfig) *AppliedConfigJSON {
+	res := &AppliedConfigJSON{
+		MeasuringMode: "",
+		ShutterSpeed:  "",
+		FieldOfView:   "",
+		ExposureTime:  "",
+		Skipped:       c.Skipped(),
+	}
+	if res.Skipped == nil {
+		res.Skipped = []string{}
+	}
+
+	if c.MeasuringModeApplied {
+		res.MeasuringMode = c.MeasuringMode.String()
+	}
+	if c.ShutterSpeedApplied {
+		res.ShutterSpeed = string(c.ShutterSpeed)
+	}
+	if c.FieldOfViewApplied {
+		res.FieldOfView = c.FieldOfView.String()
+	}
+	if c.ExposureTimeApplied {
+		res.ExposureTime = c.ExposureTime.String()
+	}
+
+	return res
+}
+
+func newMeasurementHeaderJSON(h MeasurementHeader) MeasurementHeaderJSON {
+	res := MeasurementHeaderJSON{
+		Title:         h.Title,
+		MemoryNumber:  h.MemoryNumber,
+		MeasuringMode: h.MeasuringMode.String(),
+		FieldOfView:   h.FieldOfView.String(),
+		ExposureTime:  h.ExposureTime.String(),
+		Settings:      make([]MeasurementHeaderSettingJSON, len(h.Settings)),
+	}
+
+	for i, setting := range h.Settings {
+		res.Settings[i] = MeasurementHeaderSettingJSON{
+			Code:  setting.Code,
+			Value: setting.Value,
+		}
+	}
+
+	return res
+}
+
+func newTM30JSON(tm30 *TM30Value) *TM30JSON {
+	res := &TM30JSON{
+		Rf:        tm30.Rf.Val,
+		Rg:        tm30.Rg.Val,
+		HueBinRf:  make([]float64, len(tm30.HueBinRf)),
+		HueBinRcs: make([]float64, len(tm30.HueBinRcs)),
+		HueBinRhs: make([]float64, len(tm30.HueBinRhs)),
+		SampleRf:  make([]float64, len(tm30.SampleRf)),
+	}
+
+	for i := range tm30.HueBinRf {
+		res.HueBinRf[i] = tm30.HueBinRf[i].Val
+		res.HueBinRcs[i] = tm30.HueBinRcs[i].Val
+		res.HueBinRhs[i] = tm30.HueBinRhs[i].Val
+	}
+	for i, val := range &tm30.SampleRf {
+		res.SampleRf[i] = val.Val
+	}
+
+	return res
+}
+
+type MeasurementStatsJSON struct {
+	Timestamp    int64                   `json:"Timestamp"`
+	Count        int                     `json:"Count"`
+	LUX          StatsJSON               `json:"LUX"`
+	Fc           StatsJSON               `json:"Fc"`
+	CCT          StatsJSON               `json:"CCT"`
+	DeltaUv      StatsJSON               `json:"DeltaUv"`
+	X            StatsJSON               `json:"X"`
+	Y            StatsJSON               `json:"Y"`
+	Ra           StatsJSON               `json:"Ra"`
+	SpectralData []SpectralDataStatsJSON `json:"SpectralData"`
+}
+
+type StatsJSON struct {
+	Mean     float64 `json:"Mean"`
+	StdDev   float64 `json:"StdDev"`
+	Min      float64 `json:"Min"`
+	Max      float64 `json:"Max"`
+	N        int     `json:"N"`
+	Excluded int     `json:"Excluded"`
+}
+
+type SpectralDataStatsJSON struct {
+	Range  SpectralDataRangeJSON `json:"Range"`
+	Values []StatsJSON           `json:"Values"`
+}
+
+func NewJSONMeasurementStats(stats *MeasurementStats, measTime time.Time) MeasurementStatsJSON {
+	res := MeasurementStatsJSON{
+		Timestamp:    measTime.Unix(),
+		Count:        len(stats.Measurements),
+		LUX:          StatsJSON(stats.Lux),
+		Fc:           StatsJSON(stats.FootCandle),
+		CCT:          StatsJSON(stats.CCT),
+		DeltaUv:      StatsJSON(stats.DeltaUv),
+		X:            StatsJSON(stats.X),
+		Y:            StatsJSON(stats.Y),
+		Ra:           StatsJSON(stats.Ra),
+		SpectralData: []SpectralDataStatsJSON{}, // populated later
+	}
+
+	spectralData1nm := SpectralDataStatsJSON{
+		Range: SpectralDataRangeJSON{
+			Type:    "1nm",
+			StartNm: 380,
+			EndNm:   780,
+			StepNm:  1,
+		},
+		Values: make([]StatsJSON, len(stats.SpectralData1nm)),
+	}
+	for i, val := range &stats.SpectralData1nm {
+		spectralData1nm.Values[i] = StatsJSON(val)
+	}
+
+	spectralData5nm := SpectralDataStatsJSON{
+		Range: SpectralDataRangeJSON{
+			Type:    "5nm",
+			StartNm: 380,
+			EndNm:   780,
+			StepNm:  5,
+		},
+		Values: make([]StatsJSON, len(stats.SpectralData5nm)),
+	}
+	for i, val := range &stats.SpectralData5nm {
+		spectralData5nm.Values[i] = StatsJSON(val)
+	}
+
+	res.SpectralData = []SpectralDataStatsJSON{spectralData1nm, spectralData5nm}
+
 	return res
 }
@@ -1,6 +1,7 @@
 package skreader_test
 
 import (
+	"reflect"
 	"testing"
 	"time"
 
@@ -51,6 +52,16 @@ func TestMeasurementJSON(t *testing.T) {
 				t.Errorf("Note = %v, want %v", mjs.Note, tt.measNote)
 			}
 
+			if mjs.Header.Title != m.Header.Title || mjs.Header.MemoryNumber != m.Header.MemoryNumber {
+				t.Errorf("Header = %+v, want %+v", mjs.Header, m.Header)
+			}
+			if mjs.Header.MeasuringMode != "ambient" {
+				t.Errorf("Header.MeasuringMode = %v, want %v", mjs.Header.MeasuringMode, "ambient")
+			}
+			if len(mjs.Header.Settings) != len(m.Header.Settings) {
+				t.Errorf("Header.Settings = %v, want %v", mjs.Header.Settings, m.Header.Settings)
+			}
+
 			if mjs.Tristimulus.X != m.Tristimulus.X.Val {
 				t.Errorf("Tristimulus.X = %v, want %v", mjs.Tristimulus.X, m.Tristimulus.X.Val)
 			}
@@ -96,6 +107,15 @@ func TestMeasurementJSON(t *testing.T) {
 			if mjs.DWL.ExcitationPurity != m.DWL.ExcitationPurity.Val {
 				t.Errorf("DWL.ExcitationPurity = %v, want %v", mjs.DWL.ExcitationPurity, m.DWL.ExcitationPurity.Val)
 			}
+			if (mjs.TM30 != nil) != (m.TM30 != nil) {
+				t.Errorf("TM30 = %v, want %v", mjs.TM30, m.TM30)
+			}
+			if (mjs.SSI != nil) != (m.SSI != nil) {
+				t.Errorf("SSI = %v, want %v", mjs.SSI, m.SSI)
+			}
+			if (mjs.TLCI != nil) != (m.TLCI != nil) {
+				t.Errorf("TLCI = %v, want %v", mjs.TLCI, m.TLCI)
+			}
 			if mjs.SpectralData[0].Range.Type != "1nm" {
 				t.Errorf("SpectralData[0].Range.Type = %v, want %v", mjs.SpectralData[0].Range.Type, "1nm")
 			}
@@ -105,3 +125,33 @@ func TestMeasurementJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestMeasurementJSONAppliedConfig(t *testing.T) {
+	m, err := skreader.NewMeasurementFromBytes(skreader.Testdata)
+	if err != nil {
+		t.Fatalf("NewMeasurementFromBytes() error = %v", err)
+	}
+
+	if got := skreader.NewJSONMeasurement(m, "", "", time.Now()).AppliedConfig; got != nil {
+		t.Errorf("AppliedConfig = %+v, want nil", got)
+	}
+
+	m.Config = &skreader.AppliedMeasurementConfig{
+		DeviceMeasurementConfig: skreader.DefaultMeasurementConfig(),
+		MeasuringModeApplied:    true,
+		ShutterSpeedApplied:     true,
+		FieldOfViewApplied:      false,
+		ExposureTimeApplied:     false,
+	}
+	got := skreader.NewJSONMeasurement(m, "", "", time.Now()).AppliedConfig
+	want := &skreader.AppliedConfigJSON{
+		MeasuringMode: "ambient",
+		ShutterSpeed:  string(skreader.SkShutterSpeed125Sec),
+		FieldOfView:   "",
+		ExposureTime:  "",
+		Skipped:       []string{"field of view", "exposure time"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("AppliedConfig = %+v, want %+v", got, want)
+	}
+}
@@ -13,6 +13,7 @@ type SPDXHeader struct {
 	Description string `xml:"Description"`
 	Comments    string `xml:"Comments"`
 	Date        string `xml:"Report_date"`
+	Conditions  string `xml:"Measurement_conditions,omitempty"` // measurement title and settings used by device
 }
 
 type SPDXSpectralDataPoint struct {
@@ -36,6 +37,15 @@ func NewSPDXHeader(measName, measNote string, measTime time.Time) SPDXHeader {
 	return header
 }
 
+// NewSPDXHeaderFromMeasurement is like NewSPDXHeader but also includes measurement title and settings
+// reported by device.
+func NewSPDXHeaderFromMeasurement(meas *Measurement, measName, measNote string, measTime time.Time) SPDXHeader {
+	header := NewSPDXHeader(measName, measNote, measTime)
+	header.Conditions = meas.Header.String()
+
+	return header
+}
+
 func NewSPDXSpectralDistribution(meas *Measurement) SPDXSpectralDistribution {
 	spectralData := make([]SPDXSpectralDataPoint, len(meas.SpectralData1nm))
 	for i, val := range &meas.SpectralData1nm {
@@ -50,6 +50,14 @@ func TestMeasurementSPDX(t *testing.T) {
 				t.Errorf("Description = %v, want %v", spdxHeader.Description, tt.measNote)
 			}
 
+			if spdxHeader.Conditions != "" {
+				t.Errorf("Conditions = %v, want empty", spdxHeader.Conditions)
+			}
+			spdxHeader = skreader.NewSPDXHeaderFromMeasurement(m, tt.measName, tt.measNote, now)
+			if spdxHeader.Conditions != m.Header.String() {
+				t.Errorf("Conditions = %v, want %v", spdxHeader.Conditions, m.Header.String())
+			}
+
 			spdxSpectralDistribution := skreader.NewSPDXSpectralDistribution(m)
 
 			if spdxSpectralDistribution.SpectralData[0].Value != m.SpectralData1nm[0].Val {
@@ -0,0 +1,101 @@
+package skreader
+
+import (
+	"math"
+)
+
+// Stats represents statistics of one value over repeated measurements.
+// Out of range samples (e.g. "Under" or "Over") are excluded and only counted.
+type Stats struct {
+	Mean     float64
+	StdDev   float64 // sample standard deviation, 0 if less than 2 samples
+	Min      float64
+	Max      float64
+	N        int // number of samples used
+	Excluded int // number of out of range samples
+}
+
+// MeasurementStats represents statistics over repeated measurements, see Device.MeasureN.
+type MeasurementStats struct {
+	Measurements []*Measurement // individual measurements
+
+	Lux        Stats
+	FootCandle Stats
+	CCT        Stats
+	DeltaUv    Stats
+	X          Stats // CIE 1931 x
+	Y          Stats // CIE 1931 y
+	Ra         Stats
+
+	SpectralData5nm [81]Stats
+	SpectralData1nm [401]Stats
+}
+
+// NewMeasurementStats calculates statistics over measurements.
+func NewMeasurementStats(measurements []*Measurement) *MeasurementStats {
+	s := &MeasurementStats{Measurements: measurements} //nolint:exhaustruct
+
+	field := func(value func(m *Measurement) DecimalValue) Stats {
+		values := make([]DecimalValue, len(measurements))
+		for i, m := range measurements {
+			values[i] = value(m)
+		}
+
+		return NewStats(values)
+	}
+
+	s.Lux = field(func(m *Measurement) DecimalValue { return m.Illuminance.Lux })
+	s.FootCandle = field(func(m *Measurement) DecimalValue { return m.Illuminance.FootCandle })
+	s.CCT = field(func(m *Measurement) DecimalValue { return m.ColorTemperature.Tcp })
+	s.DeltaUv = field(func(m *Measurement) DecimalValue { return m.ColorTemperature.DeltaUv })
+	s.X = field(func(m *Measurement) DecimalValue { return m.CIE1931.X })
+	s.Y = field(func(m *Measurement) DecimalValue { return m.CIE1931.Y })
+	s.Ra = field(func(m *Measurement) DecimalValue { return m.ColorRenditionIndexes.Ra })
+
+	for i := range s.SpectralData5nm {
+		s.SpectralData5nm[i] = field(func(m *Measurement) DecimalValue { return m.SpectralData5nm[i] })
+	}
+	for i := range s.SpectralData1nm {
+		s.SpectralData1nm[i] = field(func(m *Measurement) DecimalValue { return m.SpectralData1nm[i] })
+	}
+
+	return s
+}
+
+// NewStats calculates statistics of values, excluding out of range ones.
+func NewStats(values []DecimalValue) Stats {
+	var s Stats
+
+	sum := 0.0
+	for _, v := range values {
+		if v.Range != RangeOk {
+			s.Excluded++
+
+			continue
+		}
+		if s.N == 0 || v.Val < s.Min {
+			s.Min = v.Val
+		}
+		if s.N == 0 || v.Val > s.Max {
+			s.Max = v.Val
+		}
+		sum += v.Val
+		s.N++
+	}
+	if s.N == 0 {
+		return s
+	}
+	s.Mean = sum / float64(s.N)
+
+	if s.N > 1 {
+		sq := 0.0
+		for _, v := range values {
+			if v.Range == RangeOk {
+				sq += (v.Val - s.Mean) * (v.Val - s.Mean)
+			}
+		}
+		s.StdDev = math.Sqrt(sq / float64(s.N-1))
+	}
+
+	return s
+}
@@ -0,0 +1,105 @@
+package skreader_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/akares/skreader"
+)
+
+func TestNewStats(t *testing.T) {
+	ok := func(vals ...float64) []skreader.DecimalValue {
+		res := make([]skreader.DecimalValue, len(vals))
+		for i, v := range vals {
+			res[i] = skreader.DecimalValue{Val: v, Str: "", Range: skreader.RangeOk}
+		}
+
+		return res
+	}
+	over := skreader.DecimalValue{Val: 1e6, Str: "", Range: skreader.RangeOver}
+
+	for _, tt := range []struct {
+		name   string
+		values []skreader.DecimalValue
+		want   skreader.Stats
+	}{
+		{
+			name:   "empty",
+			values: nil,
+			want:   skreader.Stats{Mean: 0, StdDev: 0, Min: 0, Max: 0, N: 0, Excluded: 0},
+		},
+		{
+			name:   "single",
+			values: ok(-3),
+			want:   skreader.Stats{Mean: -3, StdDev: 0, Min: -3, Max: -3, N: 1, Excluded: 0},
+		},
+		{
+			name:   "several",
+			values: ok(2, 4, 4, 4, 5, 5, 7, 9),
+			want:   skreader.Stats{Mean: 5, StdDev: math.Sqrt(32.0 / 7), Min: 2, Max: 9, N: 8, Excluded: 0},
+		},
+		{
+			name:   "out of range excluded",
+			values: append(ok(1, 3), over),
+			want:   skreader.Stats{Mean: 2, StdDev: math.Sqrt(2), Min: 1, Max: 3, N: 2, Excluded: 1},
+		},
+		{
+			name:   "all out of range",
+			values: []skreader.DecimalValue{over, over},
+			want:   skreader.Stats{Mean: 0, StdDev: 0, Min: 0, Max: 0, N: 0, Excluded: 2},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got := skreader.NewStats(tt.values)
+			assert.InDelta(t, tt.want.StdDev, got.StdDev, 1e-9, "StdDev invalid")
+			got.StdDev = tt.want.StdDev
+			assert.Equal(t, tt.want, got, "NewStats() invalid")
+		})
+	}
+}
+
+func TestNewMeasurementStats(t *testing.T) {
+	ok, err := skreader.NewMeasurementFromBytes(skreader.Testdata)
+	if err != nil {
+		t.Fatalf("NewMeasurementFromBytes() error = %v", err)
+	}
+	under, err := skreader.NewMeasurementFromBytes(skreader.TestdataUnder)
+	if err != nil {
+		t.Fatalf("NewMeasurementFromBytes() error = %v", err)
+	}
+
+	stats := skreader.NewMeasurementStats([]*skreader.Measurement{ok, under, ok})
+
+	assert.Len(t, stats.Measurements, 3, "Measurements invalid")
+	assert.Equal(t, 2, stats.Lux.N, "Lux N invalid")
+	assert.Equal(t, 1, stats.Lux.Excluded, "Lux Excluded invalid")
+	assert.Equal(t, ok.Illuminance.Lux.Val, stats.Lux.Mean, "Lux Mean invalid")
+	assert.Equal(t, ok.ColorRenditionIndexes.Ra.Val, stats.Ra.Max, "Ra Max invalid")
+	assert.Equal(t, ok.SpectralData1nm[200].Val, stats.SpectralData1nm[200].Min, "1nm spectral Min invalid")
+	assert.Equal(t, ok.SpectralData5nm[40].Val, stats.SpectralData5nm[40].Mean, "5nm spectral Mean invalid")
+}
+
+func TestMeasureN(t *testing.T) {
+	sim := skreader.NewSimulatedDevice("C-7000")
+	sim.MeasuringDuration = 10 * time.Millisecond
+	d, counter := newResilientDevice(t, sim)
+
+	stats, err := d.MeasureN(3, 5*time.Millisecond)
+	if err != nil {
+		t.Fatalf("MeasureN() error = %v", err)
+	}
+
+	assert.Len(t, stats.Measurements, 3, "Measurements invalid")
+	assert.Equal(t, 3, stats.Lux.N, "Lux N invalid")
+	assert.Equal(t, 407.0, stats.Lux.Mean, "Lux Mean invalid")
+	assert.Equal(t, 0.0, stats.Lux.StdDev, "Lux StdDev invalid")
+	assert.Equal(t, 3, counter.writes[string(skreader.SkCommandStartMeasuring)], "RM0 writes invalid")
+	assert.Equal(t, 1, counter.writes[string(skreader.SkCommandSetRemoteOn)], "RT1 writes invalid")
+
+	if _, err = d.MeasureN(0, 0); err == nil {
+		t.Errorf("MeasureN(0) error = nil, want error")
+	}
+}
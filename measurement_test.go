@@ -1,6 +1,8 @@
 package skreader_test
 
 import (
+	"encoding/binary"
+	"math"
 	"testing"
 
 	"github.com/akares/skreader"
@@ -37,3 +39,100 @@ func TestMeasurementRange(t *testing.T) {
 		})
 	}
 }
+
+// testdataExtended returns a copy of base data followed by extended data block
+// with all TM-30, SSI and TLCI values set to val.
+func testdataExtended(base []byte, val float32) []byte {
+	data := make([]byte, skreader.MeasurementDataExtendedSize)
+	copy(data, base)
+
+	putFloat32 := func(offset int) {
+		binary.BigEndian.PutUint32(data[offset:], math.Float32bits(val))
+	}
+	putFloat32(2381)
+	putFloat32(2386)
+	for i := 0; i < 16*3; i++ {
+		putFloat32(2391 + i*5)
+	}
+	for i := 0; i < 99; i++ {
+		putFloat32(2631 + i*4)
+	}
+	for i := 0; i < 4; i++ {
+		putFloat32(3028 + i*5)
+	}
+
+	return data
+}
+
+func TestMeasurementTM30(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		testdata  []byte
+		wantTM30  bool
+		wantRf    float64
+		wantRange skreader.ValueRange
+	}{
+		{
+			name:     "base data",
+			testdata: skreader.Testdata,
+			wantTM30: false,
+		},
+		{
+			name:      "extended data",
+			testdata:  testdataExtended(skreader.Testdata, 0.5),
+			wantTM30:  true,
+			wantRf:    0.5,
+			wantRange: skreader.RangeOk,
+		},
+		{
+			name:      "extended data range over",
+			testdata:  testdataExtended(skreader.Testdata, 500),
+			wantTM30:  true,
+			wantRf:    500,
+			wantRange: skreader.RangeOver,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := skreader.NewMeasurementFromBytes(tt.testdata)
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if (m.TM30 != nil) != tt.wantTM30 {
+				t.Fatalf("TM30 = %v, want TM30 %v", m.TM30, tt.wantTM30)
+			}
+			if !tt.wantTM30 {
+				return
+			}
+			if m.TM30.Rf.Val != tt.wantRf || m.TM30.HueBinRhs[15].Val != tt.wantRf || m.TM30.SampleRf[98].Val != tt.wantRf {
+				t.Errorf("TM30 = %+v, want all values %v", m.TM30, tt.wantRf)
+			}
+			if m.TM30.Rf.Range != tt.wantRange || m.TM30.SampleRf[0].Range != tt.wantRange {
+				t.Errorf("TM30 range = %v, want %v", m.TM30.Rf.Range, tt.wantRange)
+			}
+		})
+	}
+}
+
+func TestMeasurementSSIAndTLCI(t *testing.T) {
+	m, err := skreader.NewMeasurementFromBytes(skreader.Testdata)
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	if m.SSI != nil || m.TLCI != nil {
+		t.Errorf("SSI = %v, TLCI = %v, want nil for base data", m.SSI, m.TLCI)
+	}
+
+	m, err = skreader.NewMeasurementFromBytes(testdataExtended(skreader.Testdata, 87))
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	if m.SSI == nil || m.SSI.Tungsten.Val != 87 || m.SSI.Daylight.Val != 87 {
+		t.Errorf("SSI = %+v, want all values 87", m.SSI)
+	}
+	if m.TLCI == nil || m.TLCI.Qa.Val != 87 || m.TLCI.TLMF.Val != 87 {
+		t.Errorf("TLCI = %+v, want all values 87", m.TLCI)
+	}
+	if m.TLCI.Qa.Str != "87" || m.TLCI.Qa.Range != skreader.RangeOk {
+		t.Errorf("TLCI.Qa = %+v, want 87 in range", m.TLCI.Qa)
+	}
+}
@@ -0,0 +1,255 @@
+package skreader
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+// Assert NetworkAdapter implements UsbDevice adapter interface
+var _ UsbAdapter = (*NetworkAdapter)(nil)
+
+const (
+	NetworkPortDefault        = 7003                            // default TCP port of UsbServer
+	NetworkTimeoutDefault     = time.Duration(10) * time.Second // how long to wait for UsbServer to answer one request
+	NetworkIdleTimeoutDefault = time.Duration(1) * time.Minute  // how long UsbServer waits for the next client request
+
+	networkChallengeSize = 32
+	networkMaxFrameSize  = 1 << 20
+)
+
+// Errors returned by NetworkAdapter when it can't use the remote device.
+var (
+	ErrAuthFailed = errors.New("authentication failed")
+	ErrDeviceBusy = errors.New("device is used by another client")
+)
+
+// Network protocol requests, one for each UsbAdapter method, and response statuses.
+//
+// Client and server exchange frames of 1 byte type (request or status), 4 bytes big-endian payload length
+// and payload. Server starts with sending random challenge (not framed), client authenticates
+// with HMAC-SHA256 of the challenge keyed by the shared token. Error status payload is 1 byte code
+// of the sentinel error it wraps (see sentinelErrors, 0 if none) followed by error message.
+const (
+	netReqAuth byte = iota + 1
+	netReqOpen
+	netReqClose
+	netReqRead
+	netReqWrite
+	netReqManufacturer
+	netReqProduct
+)
+
+const (
+	netStatusOK byte = iota
+	netStatusError
+	netStatusAuthFailed
+	netStatusBusy
+)
+
+// encodeNetworkError returns error status payload.
+func encodeNetworkError(err error) []byte {
+	return append([]byte{byte(sentinelCode(err))}, err.Error()...)
+}
+
+// decodeNetworkError returns error of error status payload.
+func decodeNetworkError(data []byte) error {
+	if len(data) == 0 {
+		return &codedError{msg: "remote device: unknown error", sentinel: nil}
+	}
+
+	return &codedError{msg: "remote device: " + string(data[1:]), sentinel: sentinelOf(int(data[0]))}
+}
+
+// NetworkAdapter implements UsbAdapter interface by forwarding all calls to UsbServer over TCP,
+// so that device connected to another host can be used. Connection is made by Open and closed by Close.
+//
+// Note that the connection is authenticated but not encrypted.
+type NetworkAdapter struct {
+	addr string
+	opts NetworkAdapterOptions
+
+	conn net.Conn
+}
+
+// NetworkAdapterOptions represents NetworkAdapter settings. Zero values mean defaults.
+type NetworkAdapterOptions struct {
+	Token   string        // shared secret, must be the same as UsbServer one
+	Timeout time.Duration // limits one request, NetworkTimeoutDefault is used if 0
+}
+
+// NewNetworkAdapter creates NetworkAdapter connecting to UsbServer at addr ("host:port").
+func NewNetworkAdapter(addr string, opts NetworkAdapterOptions) *NetworkAdapter {
+	return &NetworkAdapter{addr: addr, opts: opts} //nolint:exhaustruct
+}
+
+func (a *NetworkAdapter) Open() error {
+	if a.conn != nil {
+		_ = a.Close()
+	}
+
+	conn, err := net.DialTimeout("tcp", a.addr, a.timeout())
+	if err != nil {
+		return fmt.Errorf("could not connect to %s: %w", a.addr, err)
+	}
+	a.conn = conn
+
+	err = a.authenticate()
+	if err == nil {
+		_, err = a.request(netReqOpen, nil)
+	}
+	if err != nil {
+		_ = conn.Close()
+		a.conn = nil
+
+		return err
+	}
+
+	return nil
+}
+
+// authenticate answers server challenge.
+func (a *NetworkAdapter) authenticate() error {
+	_ = a.conn.SetDeadline(time.Now().Add(a.timeout()))
+
+	challenge := make([]byte, networkChallengeSize)
+	if _, err := io.ReadFull(a.conn, challenge); err != nil {
+		return fmt.Errorf("could not read challenge from %s: %w", a.addr, err)
+	}
+
+	_, err := a.request(netReqAuth, networkMAC(a.opts.Token, challenge))
+
+	return err
+}
+
+func (a *NetworkAdapter) Close() error {
+	if a.conn == nil {
+		return nil
+	}
+
+	_, err := a.request(netReqClose, nil)
+	if cerr := a.conn.Close(); err == nil {
+		err = cerr
+	}
+	a.conn = nil
+
+	return err
+}
+
+func (a *NetworkAdapter) Read(buf []byte) (int, error) {
+	size := make([]byte, 4) //nolint:gomnd
+	binary.BigEndian.PutUint32(size, uint32(len(buf)))
+
+	data, err := a.request(netReqRead, size)
+	if err != nil {
+		return 0, err
+	}
+	if len(data) > len(buf) {
+		return 0, fmt.Errorf("remote device returned %d bytes, which is more than requested %d bytes", len(data), len(buf))
+	}
+
+	return copy(buf, data), nil
+}
+
+func (a *NetworkAdapter) Write(buf []byte) (int, error) {
+	data, err := a.request(netReqWrite, buf)
+	if err != nil {
+		return 0, err
+	}
+	if len(data) != 4 { //nolint:gomnd
+		return 0, errors.New("invalid write response from remote device")
+	}
+
+	return int(binary.BigEndian.Uint32(data)), nil
+}
+
+func (a *NetworkAdapter) Manufacturer() (string, error) {
+	data, err := a.request(netReqManufacturer, nil)
+
+	return string(data), err
+}
+
+func (a *NetworkAdapter) Product() (string, error) {
+	data, err := a.request(netReqProduct, nil)
+
+	return string(data), err
+}
+
+// request sends request to server and returns response payload or error reported by server.
+func (a *NetworkAdapter) request(req byte, payload []byte) ([]byte, error) {
+	if a.conn == nil {
+		return nil, errors.New("remote device is not open")
+	}
+
+	_ = a.conn.SetDeadline(time.Now().Add(a.timeout()))
+
+	if err := writeNetworkFrame(a.conn, req, payload); err != nil {
+		return nil, fmt.Errorf("could not send request to %s: %w", a.addr, err)
+	}
+	status, data, err := readNetworkFrame(a.conn)
+	if err != nil {
+		return nil, fmt.Errorf("could not read response from %s: %w", a.addr, err)
+	}
+
+	switch status {
+	case netStatusOK:
+		return data, nil
+	case netStatusAuthFailed:
+		return nil, fmt.Errorf("%s: %w", a.addr, ErrAuthFailed)
+	case netStatusBusy:
+		return nil, fmt.Errorf("%s: %w", a.addr, ErrDeviceBusy)
+	default:
+		return nil, decodeNetworkError(data)
+	}
+}
+
+func (a *NetworkAdapter) timeout() time.Duration {
+	if a.opts.Timeout <= 0 {
+		return NetworkTimeoutDefault
+	}
+
+	return a.opts.Timeout
+}
+
+// networkMAC returns authentication code of challenge.
+func networkMAC(token string, challenge []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(token))
+	mac.Write(challenge)
+
+	return mac.Sum(nil)
+}
+
+func writeNetworkFrame(w io.Writer, typ byte, payload []byte) error {
+	frame := make([]byte, 5+len(payload)) //nolint:gomnd
+	frame[0] = typ
+	binary.BigEndian.PutUint32(frame[1:5], uint32(len(payload)))
+	copy(frame[5:], payload)
+
+	_, err := w.Write(frame)
+
+	return err
+}
+
+func readNetworkFrame(r io.Reader) (typ byte, payload []byte, err error) {
+	header := make([]byte, 5) //nolint:gomnd
+	if _, err = io.ReadFull(r, header); err != nil {
+		return 0, nil, err
+	}
+
+	size := binary.BigEndian.Uint32(header[1:5])
+	if size > networkMaxFrameSize {
+		return 0, nil, fmt.Errorf("frame size %d exceeds limit", size)
+	}
+
+	payload = make([]byte, size)
+	if _, err = io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+
+	return header[0], payload, nil
+}
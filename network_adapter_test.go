@@ -0,0 +1,132 @@
+package skreader_test
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/akares/skreader"
+)
+
+// startUsbServer serves sim on random local port and returns its address.
+func startUsbServer(t *testing.T, sim *skreader.SimulatedDevice, token string) string {
+	t.Helper()
+
+	return serveUsb(t, &skreader.UsbServer{Adapter: sim, Token: token}) //nolint:exhaustruct
+}
+
+func serveUsb(t *testing.T, srv *skreader.UsbServer) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() { _ = srv.Serve(ln) }()
+
+	return ln.Addr().String()
+}
+
+func TestNetworkAdapterMeasure(t *testing.T) {
+	sim := skreader.NewSimulatedDevice("C-7000")
+	sim.MeasuringDuration = 10 * time.Millisecond
+	addr := startUsbServer(t, sim, "secret")
+
+	d, err := skreader.NewDeviceWithAdapter(skreader.NewNetworkAdapter(addr, skreader.NetworkAdapterOptions{Token: "secret"})) //nolint:exhaustruct
+	if err != nil {
+		t.Fatalf("NewDeviceWithAdapter() error = %v", err)
+	}
+	defer d.Close()
+
+	if got := d.String(); got != "SEKONIC C-7000" {
+		t.Errorf("String() = %s, want %s", got, "SEKONIC C-7000")
+	}
+
+	m, err := d.Measure()
+	if err != nil {
+		t.Fatalf("Measure() error = %v", err)
+	}
+	if m.Illuminance.Lux.Str != "407" {
+		t.Errorf("Measure() Lux = %s, want %s", m.Illuminance.Lux.Str, "407")
+	}
+}
+
+func TestNetworkAdapterErrors(t *testing.T) {
+	sim := skreader.NewSimulatedDevice("C-7000")
+	addr := startUsbServer(t, sim, "secret")
+
+	// Wrong token
+	wrong := skreader.NewNetworkAdapter(addr, skreader.NetworkAdapterOptions{Token: "guess"}) //nolint:exhaustruct
+	if err := wrong.Open(); !errors.Is(err, skreader.ErrAuthFailed) {
+		t.Errorf("Open() error = %v, want %v", err, skreader.ErrAuthFailed)
+	}
+
+	// One client at a time
+	first := skreader.NewNetworkAdapter(addr, skreader.NetworkAdapterOptions{Token: "secret"}) //nolint:exhaustruct
+	if err := first.Open(); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	second := skreader.NewNetworkAdapter(addr, skreader.NetworkAdapterOptions{Token: "secret"}) //nolint:exhaustruct
+	if err := second.Open(); !errors.Is(err, skreader.ErrDeviceBusy) {
+		t.Errorf("Open() error = %v, want %v", err, skreader.ErrDeviceBusy)
+	}
+
+	// Remote adapter errors
+	sim.Faults.ReadErr = errors.New("cable cut")
+	if _, err := first.Read(make([]byte, 2)); err == nil || err.Error() != "remote device: cable cut" {
+		t.Errorf("Read() error = %v, want %s", err, "remote device: cable cut")
+	}
+	for _, sentinel := range []error{skreader.ErrTimeout, skreader.ErrTransfer} {
+		sim.Faults.ReadErr = fmt.Errorf("%w: cable cut", sentinel)
+		if _, err := first.Read(make([]byte, 2)); !errors.Is(err, sentinel) {
+			t.Errorf("Read() error = %v, want %v", err, sentinel)
+		}
+	}
+	sim.Faults.ReadErr = nil
+
+	if err := first.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+
+	// Device is released when client disconnects.
+	deadline := time.Now().Add(time.Second)
+	for {
+		err := second.Open()
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, skreader.ErrDeviceBusy) || time.Now().After(deadline) {
+			t.Fatalf("Open() error = %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	_ = second.Close()
+}
+
+func TestNetworkAdapterIdleClient(t *testing.T) {
+	sim := skreader.NewSimulatedDevice("C-7000")
+	addr := serveUsb(t, &skreader.UsbServer{Adapter: sim, Token: "secret", IdleTimeout: 50 * time.Millisecond}) //nolint:exhaustruct
+
+	stalled := skreader.NewNetworkAdapter(addr, skreader.NetworkAdapterOptions{Token: "secret"}) //nolint:exhaustruct
+	if err := stalled.Open(); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer stalled.Close()
+
+	// Stalled client is disconnected and device is released.
+	time.Sleep(200 * time.Millisecond)
+
+	next := skreader.NewNetworkAdapter(addr, skreader.NetworkAdapterOptions{Token: "secret"}) //nolint:exhaustruct
+	if err := next.Open(); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	_ = next.Close()
+
+	if _, err := stalled.Manufacturer(); err == nil {
+		t.Errorf("Manufacturer() error = nil, want error of disconnected client")
+	}
+}
@@ -0,0 +1,207 @@
+package skreader
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"sync"
+	"time"
+)
+
+// UsbServer exposes UsbAdapter of locally connected device over TCP to be used by NetworkAdapter on another host.
+// Clients must authenticate with the shared token. Only one client at a time can use the device,
+// others get ErrDeviceBusy until it disconnects.
+//
+// Device is opened and closed by the client, it is also closed when client disconnects
+// or does not send any request for IdleTimeout.
+type UsbServer struct {
+	Adapter     UsbAdapter    // local device adapter
+	Token       string        // shared secret, must not be empty
+	Log         *log.Logger   // logs client connections and errors if set
+	IdleTimeout time.Duration // client is disconnected after this time without request, NetworkIdleTimeoutDefault if 0
+
+	mu   sync.Mutex
+	busy bool
+}
+
+// Serve accepts connections on ln and serves clients until ln is closed. Accept error is returned then.
+func (s *UsbServer) Serve(ln net.Listener) error {
+	if s.Adapter == nil {
+		return errors.New("adapter is nil")
+	}
+	if s.Token == "" {
+		return errors.New("token is required")
+	}
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return err
+		}
+		go s.serveConn(conn)
+	}
+}
+
+func (s *UsbServer) serveConn(conn net.Conn) {
+	defer conn.Close()
+
+	client := conn.RemoteAddr().String()
+
+	// Do not let unauthenticated clients hang around.
+	_ = conn.SetDeadline(time.Now().Add(NetworkTimeoutDefault))
+	if err := s.authenticate(conn); err != nil {
+		s.logf("%s: %v", client, err)
+
+		return
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	if !s.lock() {
+		s.logf("%s: rejected, device is used by another client", client)
+		_ = writeNetworkFrame(conn, netStatusBusy, nil)
+
+		return
+	}
+	defer s.unlock()
+
+	if err := writeNetworkFrame(conn, netStatusOK, nil); err != nil {
+		return
+	}
+
+	s.logf("%s: connected", client)
+
+	opened := false
+	defer func() {
+		if opened {
+			_ = s.Adapter.Close()
+		}
+		s.logf("%s: disconnected", client)
+	}()
+
+	for {
+		// Stalled client must not keep the device forever.
+		_ = conn.SetReadDeadline(time.Now().Add(s.idleTimeout()))
+		req, payload, err := readNetworkFrame(conn)
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			s.logf("%s: idle for %s, disconnecting", client, s.idleTimeout())
+		}
+		if err != nil {
+			return
+		}
+
+		status, data := s.handle(req, payload, &opened)
+		_ = conn.SetWriteDeadline(time.Now().Add(NetworkTimeoutDefault))
+		if err = writeNetworkFrame(conn, status, data); err != nil {
+			return
+		}
+	}
+}
+
+// authenticate sends challenge and checks client answer. Client is notified if authentication failed.
+func (s *UsbServer) authenticate(conn net.Conn) error {
+	challenge := make([]byte, networkChallengeSize)
+	if _, err := rand.Read(challenge); err != nil {
+		return fmt.Errorf("could not generate challenge: %w", err)
+	}
+	if _, err := conn.Write(challenge); err != nil {
+		return err
+	}
+
+	req, mac, err := readNetworkFrame(conn)
+	if err != nil {
+		return err
+	}
+	if req != netReqAuth || !hmac.Equal(mac, networkMAC(s.Token, challenge)) {
+		_ = writeNetworkFrame(conn, netStatusAuthFailed, nil)
+
+		return ErrAuthFailed
+	}
+
+	return nil
+}
+
+// handle executes one client request on the adapter and returns response status and payload.
+func (s *UsbServer) handle(req byte, payload []byte, opened *bool) (status byte, data []byte) {
+	var err error
+
+	switch req {
+	case netReqOpen:
+		if *opened {
+			_ = s.Adapter.Close()
+		}
+		err = s.Adapter.Open()
+		*opened = err == nil
+	case netReqClose:
+		if *opened {
+			err = s.Adapter.Close()
+			*opened = false
+		}
+	case netReqRead:
+		if len(payload) != 4 { //nolint:gomnd
+			return netStatusError, encodeNetworkError(errors.New("invalid read request"))
+		}
+		size := binary.BigEndian.Uint32(payload)
+		if size > networkMaxFrameSize {
+			return netStatusError, encodeNetworkError(errors.New("read size exceeds limit"))
+		}
+		buf := make([]byte, size)
+		var n int
+		n, err = s.Adapter.Read(buf)
+		data = buf[:n]
+	case netReqWrite:
+		var n int
+		n, err = s.Adapter.Write(payload)
+		data = make([]byte, 4) //nolint:gomnd
+		binary.BigEndian.PutUint32(data, uint32(n))
+	case netReqManufacturer:
+		var str string
+		str, err = s.Adapter.Manufacturer()
+		data = []byte(str)
+	case netReqProduct:
+		var str string
+		str, err = s.Adapter.Product()
+		data = []byte(str)
+	default:
+		return netStatusError, encodeNetworkError(fmt.Errorf("unknown request %d", req))
+	}
+
+	if err != nil {
+		return netStatusError, encodeNetworkError(err)
+	}
+
+	return netStatusOK, data
+}
+
+func (s *UsbServer) idleTimeout() time.Duration {
+	return durationOrDefault(s.IdleTimeout, NetworkIdleTimeoutDefault)
+}
+
+func (s *UsbServer) lock() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.busy {
+		return false
+	}
+	s.busy = true
+
+	return true
+}
+
+func (s *UsbServer) unlock() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.busy = false
+}
+
+func (s *UsbServer) logf(format string, args ...interface{}) {
+	if s.Log != nil {
+		s.Log.Printf(format, args...)
+	}
+}
@@ -0,0 +1,133 @@
+package skreader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ReconnectPolicy configures resilient mode of Device. In this mode, when command fails with transfer error
+// (e.g. device was power-cycled or replugged), adapter is closed and re-opened and device capabilities
+// are detected again. Idempotent commands (ST, MN, FV, NR) are then repeated. Other commands are never
+// repeated, because device may have already executed them (e.g. RM0 would start one more measurement),
+// their original error is returned once device is reconnected.
+//
+// Zero value disables resilient mode. Zero Backoff and MaxBackoff mean defaults.
+type ReconnectPolicy struct {
+	Attempts   int           // how many times to try to reconnect after transfer error, 0 disables reconnecting
+	Backoff    time.Duration // delay before the first attempt, doubled for every next one
+	MaxBackoff time.Duration // maximal delay between attempts
+}
+
+// NewReconnectPolicy returns ReconnectPolicy with default settings.
+func NewReconnectPolicy() ReconnectPolicy {
+	return ReconnectPolicy{
+		Attempts:   ReconnectAttemptsDefault,
+		Backoff:    ReconnectBackoffDefault,
+		MaxBackoff: ReconnectMaxBackoffDefault,
+	}
+}
+
+// delay returns how long to wait before given reconnect attempt (starting from 1).
+func (p ReconnectPolicy) delay(attempt int) time.Duration {
+	delay := p.Backoff
+	if delay <= 0 {
+		delay = ReconnectBackoffDefault
+	}
+	maxDelay := p.MaxBackoff
+	if maxDelay <= 0 {
+		maxDelay = ReconnectMaxBackoffDefault
+	}
+
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+
+	return delay
+}
+
+// isIdempotent reports whether command can be safely repeated if it is not known whether device executed it.
+func isIdempotent(cmd SkCommand) bool {
+	switch cmd {
+	case SkCommandGetStatus, SkCommandGetModelNumber, SkCommandGetFirmwareVersion, SkCommandGetMeasurementResult:
+		return true
+	default:
+		return false
+	}
+}
+
+// reconnectAndRetry reconnects device after cmd failed with transfer error cause and repeats cmd if it is idempotent.
+// Must be called with command lock held.
+func (d *Device) reconnectAndRetry(ctx context.Context, cmd SkCommand, datapos, datalen int, cause error) ([]byte, error) {
+	d.logf("%s command failed, reconnecting: %v", cmd, cause)
+
+	var reconnectErr error
+	for attempt := 1; attempt <= d.Reconnect.Attempts; attempt++ {
+		select {
+		case <-time.After(d.Reconnect.delay(attempt)):
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%s command failed, reconnecting canceled (%v): %w", cmd, ctx.Err(), cause)
+		}
+
+		reconnectErr = d.reconnect(ctx)
+		if reconnectErr != nil {
+			d.logf("reconnect attempt %d of %d failed: %v", attempt, d.Reconnect.Attempts, reconnectErr)
+
+			continue
+		}
+		d.logf("%s reconnected: model %s, firmware %s", d.name(), d.capabilities.Model, d.capabilities.FirmwareInfo)
+
+		if !isIdempotent(cmd) {
+			return nil, fmt.Errorf("%s command not repeated after reconnecting: %w", cmd, cause)
+		}
+
+		data, err := d.exec(ctx, cmd, datapos, datalen)
+		if err == nil || !errors.Is(err, ErrTransfer) {
+			return data, err
+		}
+		cause = err
+	}
+
+	if reconnectErr != nil {
+		return nil, fmt.Errorf("%s command failed, could not reconnect in %d attempts (%v): %w",
+			cmd, d.Reconnect.Attempts, reconnectErr, cause)
+	}
+
+	return nil, fmt.Errorf("%s command failed after %d reconnects: %w", cmd, d.Reconnect.Attempts, cause)
+}
+
+// reconnect closes and re-opens adapter and detects device capabilities again,
+// as it may be another device connected now. Must be called with command lock held.
+func (d *Device) reconnect(ctx context.Context) error {
+	_ = d.adapter.Close()
+
+	err := d.adapter.Open()
+	if err != nil {
+		return err
+	}
+
+	manufacturer, err := d.adapter.Manufacturer()
+	if err != nil {
+		return err
+	}
+
+	product, err := d.adapter.Product()
+	if err != nil {
+		return err
+	}
+
+	err = d.detectCapabilities(ctx, d.exec)
+	if err != nil {
+		return err
+	}
+
+	d.manufacturer = manufacturer
+	d.product = product
+	d.connection++
+
+	return nil
+}
@@ -0,0 +1,209 @@
+package skreader_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/akares/skreader"
+)
+
+// commandCounter counts commands written to the wrapped adapter.
+type commandCounter struct {
+	skreader.UsbAdapter
+	writes map[string]int
+}
+
+func (c *commandCounter) Write(buf []byte) (int, error) {
+	c.writes[string(buf)]++
+
+	return c.UsbAdapter.Write(buf)
+}
+
+func newResilientDevice(t *testing.T, sim *skreader.SimulatedDevice) (*skreader.Device, *commandCounter) {
+	t.Helper()
+
+	counter := &commandCounter{UsbAdapter: sim, writes: map[string]int{}}
+	d, err := skreader.NewDeviceWithAdapter(counter)
+	if err != nil {
+		t.Fatalf("NewDeviceWithAdapter() error = %v", err)
+	}
+	d.Reconnect = skreader.ReconnectPolicy{Attempts: 3, Backoff: time.Millisecond, MaxBackoff: 0}
+
+	return d, counter
+}
+
+func TestReconnectRetriesIdempotentCommand(t *testing.T) {
+	sim := skreader.NewSimulatedDevice("C-7000")
+	d, counter := newResilientDevice(t, sim)
+
+	// Another model is connected after replug, the second disconnect breaks the first reconnect attempt.
+	sim.Model = "C-800"
+	sim.Faults.Disconnects = 2
+
+	st, err := d.State()
+	if err != nil {
+		t.Fatalf("State() error = %v", err)
+	}
+	if st.Ring != skreader.SkRingStatusLow {
+		t.Errorf("State() Ring = %v, want %v", st.Ring, skreader.SkRingStatusLow)
+	}
+	if got := counter.writes["ST"]; got != 2 {
+		t.Errorf("ST written %d times, want %d", got, 2)
+	}
+	if got := d.Capabilities().Model; got != "C-800" {
+		t.Errorf("Capabilities() Model = %s, want %s", got, "C-800")
+	}
+}
+
+func TestReconnectDoesNotRepeatStartMeasuring(t *testing.T) {
+	sim := skreader.NewSimulatedDevice("C-7000")
+	d, counter := newResilientDevice(t, sim)
+
+	sim.Faults.Disconnects = 1
+
+	err := d.StartMeasuring()
+	if !errors.Is(err, skreader.ErrTransfer) {
+		t.Fatalf("StartMeasuring() error = %v, want %v", err, skreader.ErrTransfer)
+	}
+	if got := counter.writes[string(skreader.SkCommandStartMeasuring)]; got != 1 {
+		t.Errorf("RM0 written %d times, want %d", got, 1)
+	}
+
+	// Device is already reconnected.
+	if _, err = d.State(); err != nil {
+		t.Errorf("State() error = %v", err)
+	}
+}
+
+func TestReconnectFailed(t *testing.T) {
+	sim := skreader.NewSimulatedDevice("C-7000")
+	d, _ := newResilientDevice(t, sim)
+
+	errUnplugged := errors.New("unplugged")
+	sim.Faults.Disconnects = 1
+	sim.Faults.OpenErr = errUnplugged
+
+	_, err := d.State()
+	if !errors.Is(err, skreader.ErrTransfer) {
+		t.Fatalf("State() error = %v, want %v", err, skreader.ErrTransfer)
+	}
+
+	// Device is plugged back, next command reconnects.
+	sim.Faults.OpenErr = nil
+
+	if _, err = d.State(); err != nil {
+		t.Errorf("State() error = %v", err)
+	}
+}
+
+func TestReconnectDisabled(t *testing.T) {
+	sim := skreader.NewSimulatedDevice("C-7000")
+	d, err := skreader.NewDeviceWithAdapter(sim)
+	if err != nil {
+		t.Fatalf("NewDeviceWithAdapter() error = %v", err)
+	}
+
+	sim.Faults.Disconnects = 1
+
+	for i := 0; i < 2; i++ {
+		if _, err = d.State(); !errors.Is(err, skreader.ErrTransfer) {
+			t.Errorf("State() error = %v, want %v", err, skreader.ErrTransfer)
+		}
+	}
+}
+
+func TestReconnectMeasure(t *testing.T) {
+	sim := skreader.NewSimulatedDevice("C-7000")
+	sim.MeasuringDuration = 10 * time.Millisecond
+	d, _ := newResilientDevice(t, sim)
+
+	sim.Faults.Disconnects = 1
+
+	m, err := d.Measure()
+	if err != nil {
+		t.Fatalf("Measure() error = %v", err)
+	}
+	if m.Illuminance.Lux.Str != "407" {
+		t.Errorf("Measure() Lux = %s, want %s", m.Illuminance.Lux.Str, "407")
+	}
+}
+
+func TestReconnectCapabilitiesRace(t *testing.T) {
+	sim := skreader.NewSimulatedDevice("C-7000")
+	d, _ := newResilientDevice(t, sim)
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			caps := d.Capabilities()
+			_ = caps.FirmwareInfo.String()
+			_ = d.SupportsExtendedMeasurementConfiguration()
+			_, _ = d.ValidateMeasurementConfig()
+		}
+	}()
+	// Device name is used e.g. in logs and error messages of other goroutines.
+	named := make(chan struct{})
+	go func() {
+		defer close(named)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			_ = d.String()
+		}
+	}()
+
+	for i := 0; i < 5; i++ {
+		sim.Faults.Disconnects = 1
+		if _, err := d.State(); err != nil {
+			t.Errorf("State() error = %v", err)
+		}
+	}
+	close(done)
+	<-stopped
+	<-named
+
+	// Returned capabilities are not changed by later reconnects.
+	caps := d.Capabilities()
+	caps.FirmwareInfo.Components[0].Version = 99
+	if got := d.Capabilities().FirmwareInfo.Components[0].Version; got == 99 {
+		t.Errorf("Capabilities() FirmwareInfo is shared with device")
+	}
+}
+
+func TestReconnectNotOnStalledResponse(t *testing.T) {
+	sim := skreader.NewSimulatedDevice("C-7000")
+	sim.MeasuringDuration = 10 * time.Millisecond
+	d, counter := newResilientDevice(t, sim)
+
+	// Device stops sending measurement result after the first transfer.
+	sim.Faults.StallAfter = 100
+
+	_, err := d.Measure()
+	if !errors.Is(err, skreader.ErrTruncatedResponse) || errors.Is(err, skreader.ErrTransfer) {
+		t.Fatalf("Measure() error = %v, want %v", err, skreader.ErrTruncatedResponse)
+	}
+	var respErr *skreader.ResponseError
+	if !errors.As(err, &respErr) || len(respErr.Data) != 100 {
+		t.Errorf("Measure() error = %v, want ResponseError with 100 bytes received", err)
+	}
+	if got := counter.writes[string(skreader.SkCommandGetMeasurementResult)]; got != 1 {
+		t.Errorf("%s command sent %d times, want 1 (no reconnect)", skreader.SkCommandGetMeasurementResult, got)
+	}
+
+	// Next command exchange is not affected.
+	sim.Faults.StallAfter = 0
+	if _, err = d.State(); err != nil {
+		t.Errorf("State() error = %v", err)
+	}
+}
@@ -0,0 +1,176 @@
+package skreader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrSessionClosed is returned by Session methods after Close is called.
+var ErrSessionClosed = errors.New("session is closed")
+
+// Session keeps device in remote control mode for many measurements, e.g. when measuring a grid of points.
+// Measurement configuration is sent to device only when session begins and then only when it is changed,
+// so measuring is faster and device display does not switch between modes.
+//
+// Device is switched back to normal control mode by Close. Session is not safe for concurrent use.
+type Session struct {
+	d *Device
+
+	config     DeviceMeasurementConfig   // configuration sent to device
+	applied    *AppliedMeasurementConfig // what device could apply of config
+	connection int                       // device connection the session was started on, see Device.Reconnect
+	closed     bool
+}
+
+// BeginSession switches device to remote control mode and applies current MeasurementConfig.
+// Session must be closed after use to return device to normal control mode.
+func (d *Device) BeginSession() (*Session, error) {
+	return d.BeginSessionContext(context.Background())
+}
+
+// BeginSessionContext is like BeginSession but aborts as soon as ctx is done.
+func (d *Device) BeginSessionContext(ctx context.Context) (*Session, error) {
+	caps := d.Capabilities()
+	if d.MeasurementConfig.MeasuringMode.IsFlash() &&
+		(!caps.MeasurementConfiguration || !caps.SupportsMeasuringMode(d.MeasurementConfig.MeasuringMode)) {
+		return nil, fmt.Errorf("flash measuring mode is not supported by %s", d)
+	}
+
+	err := d.WaitReadyContext(ctx, d.connTimeout(), d.pollInterval())
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Session{d: d} //nolint:exhaustruct
+
+	err = s.enterRemote(ctx)
+	if err != nil {
+		// Use separate context here, ctx may be already done at this moment.
+		_ = d.SetRemoteOffContext(context.Background())
+
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// enterRemote switches device to remote control mode and applies measurement configuration.
+func (s *Session) enterRemote(ctx context.Context) error {
+	s.connection = s.d.connectionNumber()
+
+	err := s.d.SetRemoteOnContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	return s.applyConfig(ctx)
+}
+
+func (s *Session) applyConfig(ctx context.Context) error {
+	config := s.d.MeasurementConfig
+
+	applied, err := s.d.applyMeasurementConfiguration(ctx)
+	if err != nil {
+		return err
+	}
+	s.config = config
+	s.applied = applied
+
+	return nil
+}
+
+// prepare ensures device is still in remote control mode with current configuration.
+// Device is not in remote mode anymore after it was reconnected (e.g. power-cycled).
+func (s *Session) prepare(ctx context.Context) error {
+	if s.closed {
+		return ErrSessionClosed
+	}
+	if s.connection != s.d.connectionNumber() {
+		return s.enterRemote(ctx)
+	}
+	if s.config != s.d.MeasurementConfig {
+		return s.applyConfig(ctx)
+	}
+
+	return nil
+}
+
+// Measure performs one ambient measurement and returns result.
+func (s *Session) Measure() (*Measurement, error) {
+	return s.MeasureContext(context.Background())
+}
+
+// MeasureContext is like Measure but aborts as soon as ctx is done. Session stays open anyway.
+func (s *Session) MeasureContext(ctx context.Context) (*Measurement, error) {
+	if s.d.MeasurementConfig.MeasuringMode.IsFlash() {
+		return nil, fmt.Errorf("flash measuring mode is configured, use MeasureFlash instead")
+	}
+
+	err := s.prepare(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := s.d.measureRemote(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m.Config = s.appliedConfig()
+
+	return m, nil
+}
+
+// MeasureFlash performs one flash measurement using configured flash measuring mode and returns result,
+// see Device.MeasureFlash.
+func (s *Session) MeasureFlash() (*FlashMeasurement, error) {
+	return s.MeasureFlashContext(context.Background())
+}
+
+// MeasureFlashContext is like MeasureFlash but aborts as soon as ctx is done. Session stays open anyway.
+func (s *Session) MeasureFlashContext(ctx context.Context) (*FlashMeasurement, error) {
+	if !s.d.MeasurementConfig.MeasuringMode.IsFlash() {
+		return nil, fmt.Errorf("flash measuring mode is not configured, use Measure instead")
+	}
+
+	err := s.prepare(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Arm the device. It stays in flash standby status until the flash is fired.
+	err = s.d.StartMeasuringContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.d.WaitReadyContext(ctx, s.d.flashTimeout(), s.d.pollInterval())
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := s.d.FlashMeasurementResultContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	m.Config = s.appliedConfig()
+
+	return m, nil
+}
+
+// appliedConfig returns copy of applied configuration for a measurement result.
+func (s *Session) appliedConfig() *AppliedMeasurementConfig {
+	applied := *s.applied
+
+	return &applied
+}
+
+// Close switches device back to normal control mode. It is safe to call it more than once.
+func (s *Session) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	return s.d.SetRemoteOffContext(context.Background())
+}
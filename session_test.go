@@ -0,0 +1,126 @@
+package skreader_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/akares/skreader"
+)
+
+func newSessionDevice(t *testing.T) (*skreader.Device, *skreader.SimulatedDevice, *commandCounter) {
+	t.Helper()
+
+	sim := skreader.NewSimulatedDevice("C-7000")
+	sim.MeasuringDuration = 10 * time.Millisecond
+	d, counter := newResilientDevice(t, sim)
+
+	return d, sim, counter
+}
+
+func remoteStatus(t *testing.T, d *skreader.Device) skreader.SkRemoteStatus {
+	t.Helper()
+
+	st, err := d.State()
+	if err != nil {
+		t.Fatalf("State() error = %v", err)
+	}
+
+	return st.Remote
+}
+
+func TestSession(t *testing.T) {
+	d, _, counter := newSessionDevice(t)
+	setMode := fmt.Sprintf("%s,%d", skreader.SkCommandSetMeasuringMode, skreader.SkMeasuringModeAmbient)
+
+	s, err := d.BeginSession()
+	if err != nil {
+		t.Fatalf("BeginSession() error = %v", err)
+	}
+	assert.Equal(t, skreader.SkRemoteStatusOn, remoteStatus(t, d), "remote status during session invalid")
+
+	for i := 0; i < 3; i++ {
+		m, err := s.Measure()
+		if err != nil {
+			t.Fatalf("Measure() error = %v", err)
+		}
+		assert.Equal(t, "407", m.Illuminance.Lux.Str, "Measure() Lux invalid")
+	}
+
+	assert.Nil(t, s.Close(), "Close() error")
+	assert.Nil(t, s.Close(), "second Close() error")
+	assert.Equal(t, skreader.SkRemoteStatusOff, remoteStatus(t, d), "remote status after session invalid")
+
+	assert.Equal(t, 1, counter.writes[string(skreader.SkCommandSetRemoteOn)], "RT1 writes invalid")
+	assert.Equal(t, 1, counter.writes[string(skreader.SkCommandSetRemoteOff)], "RT0 writes invalid")
+	assert.Equal(t, 1, counter.writes[setMode], "MM writes invalid")
+	assert.Equal(t, 3, counter.writes[string(skreader.SkCommandStartMeasuring)], "RM0 writes invalid")
+
+	if _, err = s.Measure(); !errors.Is(err, skreader.ErrSessionClosed) {
+		t.Errorf("Measure() after Close() error = %v, want %v", err, skreader.ErrSessionClosed)
+	}
+}
+
+func TestSessionConfigChanged(t *testing.T) {
+	d, _, counter := newSessionDevice(t)
+	setFov := func(fov skreader.SkFieldOfView) string {
+		return fmt.Sprintf("%s,%d", skreader.SkCommandSetFov, fov)
+	}
+
+	s, err := d.BeginSession()
+	if err != nil {
+		t.Fatalf("BeginSession() error = %v", err)
+	}
+	defer s.Close()
+
+	if _, err = s.Measure(); err != nil {
+		t.Fatalf("Measure() error = %v", err)
+	}
+
+	d.MeasurementConfig.FieldOfView = skreader.SkFieldOfView10Deg
+	if _, err = s.Measure(); err != nil {
+		t.Fatalf("Measure() error = %v", err)
+	}
+	if _, err = s.Measure(); err != nil {
+		t.Fatalf("Measure() error = %v", err)
+	}
+
+	assert.Equal(t, 1, counter.writes[setFov(skreader.SkFieldOfView2Deg)], "2° FOV writes invalid")
+	assert.Equal(t, 1, counter.writes[setFov(skreader.SkFieldOfView10Deg)], "10° FOV writes invalid")
+}
+
+func TestSessionReconnected(t *testing.T) {
+	d, sim, counter := newSessionDevice(t)
+
+	s, err := d.BeginSession()
+	if err != nil {
+		t.Fatalf("BeginSession() error = %v", err)
+	}
+	defer s.Close()
+
+	// Device is reconnected in between measurements and comes back in normal control mode.
+	sim.Faults.Disconnects = 1
+	assert.Equal(t, skreader.SkRemoteStatusOff, remoteStatus(t, d), "remote status after reconnect invalid")
+
+	if _, err = s.Measure(); err != nil {
+		t.Fatalf("Measure() error = %v", err)
+	}
+	assert.Equal(t, 2, counter.writes[string(skreader.SkCommandSetRemoteOn)], "RT1 writes invalid")
+}
+
+func TestSessionFlashModeMismatch(t *testing.T) {
+	d, _, _ := newSessionDevice(t)
+
+	s, err := d.BeginSession()
+	if err != nil {
+		t.Fatalf("BeginSession() error = %v", err)
+	}
+	defer s.Close()
+
+	if _, err = s.MeasureFlash(); err == nil {
+		t.Errorf("MeasureFlash() in ambient mode error = nil, want error")
+	}
+}
@@ -0,0 +1,396 @@
+package skreader
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Assert SimulatedDevice implements UsbDevice adapter interface
+var _ UsbAdapter = (*SimulatedDevice)(nil)
+
+var skResponseNAK = []byte{21, 48} // NAK response from device when command can't be executed
+
+// SimulatedDevice implements UsbAdapter interface by simulating SEKONIC device on the protocol level.
+// Unlike FakeusbAdapter, it understands the commands sent by Device handler, keeps device state
+// (remote mode, measurement configuration, measuring status) and responds the way real device does.
+// It can be used to run the whole Device.Measure path without connected device.
+//
+// Exported fields can be changed at any time to simulate user actions (e.g. turning the ring) and faults.
+type SimulatedDevice struct {
+	ManufacturerName string
+	ProductName      string
+	Model            string // MN response, e.g. "C-7000"
+	Firmware         string // FV response, e.g. "20,C36E,27,7881,11,B216,14,50CC,17,74EC"
+
+	Ring   SkRingStatus
+	Button SkButtonStatus
+
+	InitializingDuration time.Duration // how long device is initializing after Open
+	MeasuringDuration    time.Duration // how long one measurement takes
+	FlashDelay           time.Duration // when flash is fired after device is armed in cordless flash mode, 0 means never
+	MeasurementData      []byte        // NR response data, Testdata (followed by zeroed extended data if supported) is used if empty
+	MaxTransferSize      int           // maximal number of bytes returned by one Read, longer responses are split, 0 for no limit
+
+	Faults SimulatedDeviceFaults
+
+	mu            sync.Mutex
+	responses     [][]byte
+	remote        bool
+	config        DeviceMeasurementConfig
+	readyAt       time.Time // end of initializing
+	measuredAt    time.Time // end of measuring, zero if measurement was never started
+	flashStandby  bool
+	flashFireTime time.Time // zero if flash is not going to be fired automatically
+	disconnected  bool
+}
+
+var (
+	errSimulatedDisconnect = errors.New("simulated device: disconnected")
+	errSimulatedStall      = fmt.Errorf("%w: simulated device: IN transfer not completed", ErrTimeout)
+)
+
+// SimulatedDeviceFaults defines faults injected into SimulatedDevice.
+type SimulatedDeviceFaults struct {
+	OpenErr       error       // returned by Open
+	ReadErr       error       // returned by every Read
+	WriteErr      error       // returned by every Write
+	NAKCommands   []SkCommand // commands (or command prefixes like "MMw") answered with NAK
+	HardwareError bool        // device reports hardware error status
+	Disconnects   int         // number of next Read calls losing connection, device does not respond until re-opened then
+	StallAfter    int         // if set, device stops sending responses longer than this after this many bytes
+}
+
+// NewSimulatedDevice creates simulated device of the given model with default settings:
+// firmware version 27, ring set to low position and measurement result taken from Testdata.
+func NewSimulatedDevice(model string) *SimulatedDevice {
+	return &SimulatedDevice{ //nolint:exhaustruct
+		ManufacturerName:     "SEKONIC",
+		ProductName:          model,
+		Model:                model,
+		Firmware:             "20,C36E,27,7881,11,B216,14,50CC,17,74EC",
+		Ring:                 SkRingStatusLow,
+		Button:               SkButtonStatusNone,
+		InitializingDuration: 0,
+		MeasuringDuration:    time.Duration(500) * time.Millisecond,
+		FlashDelay:           0,
+		MeasurementData:      nil,
+		MaxTransferSize:      0,
+	}
+}
+
+func (s *SimulatedDevice) Open() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Faults.OpenErr != nil {
+		return s.Faults.OpenErr
+	}
+
+	s.responses = nil
+	s.disconnected = false
+	s.remote = false
+	s.readyAt = time.Now().Add(s.InitializingDuration)
+	s.measuredAt = time.Time{}
+	s.flashStandby = false
+
+	return nil
+}
+
+func (s *SimulatedDevice) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.responses = nil
+
+	return nil
+}
+
+// Read returns the next pending response to previously written command. If response does not fit
+// into buf or it is longer than MaxTransferSize, it is split and the rest is returned by next Read calls.
+func (s *SimulatedDevice) Read(buf []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Faults.ReadErr != nil {
+		return 0, s.Faults.ReadErr
+	}
+	if s.Faults.Disconnects > 0 && !s.disconnected {
+		// Command is already executed at this moment, only response is lost.
+		s.Faults.Disconnects--
+		s.disconnected = true
+		s.responses = nil
+	}
+	if s.disconnected {
+		return 0, errSimulatedDisconnect
+	}
+	if len(s.responses) == 0 {
+		return 0, errors.New("simulated device: no response pending")
+	}
+
+	r := s.responses[0]
+	if r == nil {
+		// Stalled response, see Faults.StallAfter. Read times out like adapter read does.
+		s.responses = s.responses[1:]
+
+		return 0, errSimulatedStall
+	}
+	if s.MaxTransferSize > 0 && len(buf) > s.MaxTransferSize {
+		buf = buf[:s.MaxTransferSize]
+	}
+
+	n := copy(buf, r)
+	if n < len(r) {
+		s.responses[0] = r[n:]
+	} else {
+		s.responses = s.responses[1:]
+	}
+
+	return n, nil
+}
+
+// Write executes command and queues acknowledge and main responses to be read.
+func (s *SimulatedDevice) Write(buf []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.Faults.WriteErr != nil {
+		return 0, s.Faults.WriteErr
+	}
+	if s.disconnected {
+		return 0, errSimulatedDisconnect
+	}
+
+	cmd := string(buf)
+
+	if s.isNAK(cmd) {
+		s.responses = append(s.responses, skResponseNAK)
+
+		return len(buf), nil
+	}
+
+	resp, ok := s.exec(cmd)
+	if !ok {
+		s.responses = append(s.responses, skResponseNAK)
+
+		return len(buf), nil
+	}
+
+	if s.Faults.StallAfter > 0 && len(resp) > s.Faults.StallAfter {
+		s.responses = append(s.responses, SkResponseOK, resp[:s.Faults.StallAfter], nil)
+
+		return len(buf), nil
+	}
+
+	s.responses = append(s.responses, SkResponseOK, resp)
+
+	return len(buf), nil
+}
+
+func (s *SimulatedDevice) Manufacturer() (string, error) {
+	return s.ManufacturerName, nil
+}
+
+func (s *SimulatedDevice) Product() (string, error) {
+	return s.ProductName, nil
+}
+
+// FireFlash fires the flash when device is armed in cordless flash mode.
+func (s *SimulatedDevice) FireFlash() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.fireFlash(time.Now())
+}
+
+// exec executes command and returns main response. If command is not known or can't be executed
+// in current state, false is returned.
+//
+//nolint:gocyclo
+func (s *SimulatedDevice) exec(cmd string) ([]byte, bool) {
+	now := time.Now()
+	s.update(now)
+
+	caps := NewDeviceCapabilities(s.Model, s.mainFirmware())
+	busy := s.status(now) != SkDeviceStatusIdle
+
+	switch {
+	case cmd == string(SkCommandGetModelNumber):
+		return []byte("MN@@@" + s.Model + "\x00\x00\x00\x00\x00"), true
+	case cmd == string(SkCommandGetFirmwareVersion):
+		return []byte("FV@@@" + s.Firmware), true
+	case cmd == string(SkCommandGetStatus):
+		return s.statusResponse(now), true
+	case cmd == string(SkCommandSetRemoteOn):
+		s.remote = true
+
+		return []byte("RT"), true
+	case cmd == string(SkCommandSetRemoteOff):
+		s.remote = false
+		s.flashStandby = false
+
+		return []byte("RT"), true
+	case strings.HasPrefix(cmd, string(SkCommandSetMeasuringMode)+","):
+		mode, err := strconv.Atoi(cmd[len(SkCommandSetMeasuringMode)+1:])
+		if err != nil || !s.remote || busy || !caps.MeasurementConfiguration ||
+			!caps.SupportsMeasuringMode(SkMeasuringMode(mode)) {
+			return nil, false
+		}
+		s.config.MeasuringMode = SkMeasuringMode(mode)
+
+		return []byte("MM"), true
+	case strings.HasPrefix(cmd, string(SkCommandSetShutterSpeed)+","):
+		if !s.remote || busy || !caps.MeasurementConfiguration {
+			return nil, false
+		}
+		s.config.ShutterSpeed = SkShutterSpeed(cmd[strings.LastIndexByte(cmd, ',')+1:])
+
+		return []byte("SS"), true
+	case strings.HasPrefix(cmd, string(SkCommandSetFov)+","):
+		fov, err := strconv.Atoi(cmd[len(SkCommandSetFov)+1:])
+		if err != nil || !s.remote || busy || !caps.ExtendedMeasurementConfiguration {
+			return nil, false
+		}
+		s.config.FieldOfView = SkFieldOfView(fov)
+
+		return []byte("AG"), true
+	case strings.HasPrefix(cmd, string(SkCommandSetExposureTime)+","):
+		exp, err := strconv.Atoi(cmd[len(SkCommandSetExposureTime)+1:])
+		if err != nil || !s.remote || busy || !caps.ExtendedMeasurementConfiguration {
+			return nil, false
+		}
+		s.config.ExposureTime = SkExposureTime(exp)
+
+		return []byte("AM"), true
+	case cmd == string(SkCommandStartMeasuring):
+		if !s.remote || busy || s.Ring != SkRingStatusLow || s.Faults.HardwareError {
+			return nil, false
+		}
+		s.startMeasuring(now)
+
+		return []byte("RM"), true
+	case cmd == string(SkCommandGetMeasurementResult):
+		if busy || s.measuredAt.IsZero() {
+			return nil, false
+		}
+
+		return s.measurementData(caps), true
+	default:
+		return nil, false
+	}
+}
+
+// startMeasuring starts measuring in ambient mode or arms device in flash modes.
+func (s *SimulatedDevice) startMeasuring(now time.Time) {
+	switch s.config.MeasuringMode {
+	case SkMeasuringModeCordFlash:
+		s.measuredAt = now.Add(s.MeasuringDuration) // flash is fired by device itself
+	case SkMeasuringModeCordlessFlash:
+		s.flashStandby = true
+		s.flashFireTime = time.Time{}
+		if s.FlashDelay > 0 {
+			s.flashFireTime = now.Add(s.FlashDelay)
+		}
+	default:
+		s.measuredAt = now.Add(s.MeasuringDuration)
+	}
+}
+
+// update performs time based status transitions.
+func (s *SimulatedDevice) update(now time.Time) {
+	if s.flashStandby && !s.flashFireTime.IsZero() && !now.Before(s.flashFireTime) {
+		s.fireFlash(s.flashFireTime)
+	}
+}
+
+func (s *SimulatedDevice) fireFlash(at time.Time) {
+	if !s.flashStandby {
+		return
+	}
+	s.flashStandby = false
+	s.measuredAt = at.Add(s.MeasuringDuration)
+}
+
+func (s *SimulatedDevice) status(now time.Time) SkDeviceStatus {
+	switch {
+	case s.Faults.HardwareError:
+		return SkDeviceStatusErrorHw
+	case now.Before(s.readyAt):
+		return SkDeviceStatusBusyInitializing
+	case s.flashStandby:
+		return SkDeviceStatusBusyFlashStandby
+	case now.Before(s.measuredAt):
+		return SkDeviceStatusBusyMeasuring
+	default:
+		return SkDeviceStatusIdle
+	}
+}
+
+// statusResponse encodes device status the same way StateContext decodes it.
+//
+//nolint:gomnd
+func (s *SimulatedDevice) statusResponse(now time.Time) []byte {
+	st1, st2 := byte(0x40), byte(0x40)
+
+	switch s.status(now) {
+	case SkDeviceStatusErrorHw:
+		st1 |= 0x10
+	case SkDeviceStatusBusyInitializing:
+		st1 |= 1
+		st2 |= 1
+	case SkDeviceStatusBusyFlashStandby:
+		st1 |= 1
+		st2 |= 0x10
+	case SkDeviceStatusBusyMeasuring:
+		st1 |= 1
+		st2 |= 8
+	case SkDeviceStatusIdle, SkDeviceStatusIdleOutMeas, SkDeviceStatusBusyDarkCalibration:
+	}
+	if s.remote {
+		st1 |= 2
+	}
+	key := byte(s.Button&0x1F) | byte(s.Ring<<5)&0x60
+
+	return []byte{'S', 'T', st1, st2, key}
+}
+
+func (s *SimulatedDevice) measurementData(caps DeviceCapabilities) []byte {
+	if len(s.MeasurementData) > 0 {
+		return s.MeasurementData
+	}
+	if !caps.ExtendedData {
+		return Testdata
+	}
+
+	data := make([]byte, MeasurementDataExtendedSize)
+	copy(data, Testdata)
+
+	return data
+}
+
+func (s *SimulatedDevice) mainFirmware() int {
+	info, err := parseFirmwareInfo(s.Firmware)
+	if err != nil {
+		return 0
+	}
+
+	return info.Main()
+}
+
+func (s *SimulatedDevice) isNAK(cmd string) bool {
+	for _, c := range s.Faults.NAKCommands {
+		if strings.HasPrefix(cmd, string(c)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String returns simulated device description.
+func (s *SimulatedDevice) String() string {
+	return fmt.Sprintf("simulated %s %s (FW %s)", s.ManufacturerName, s.Model, s.Firmware)
+}
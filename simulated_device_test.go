@@ -0,0 +1,127 @@
+package skreader_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/akares/skreader"
+)
+
+func TestSimulatedDeviceMeasure(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		setup   func(s *skreader.SimulatedDevice)
+		model   string
+		wantErr error
+	}{
+		{
+			name:    "C-7000",
+			model:   "C-7000",
+			setup:   func(s *skreader.SimulatedDevice) {},
+			wantErr: nil,
+		},
+		{
+			name:    "C-700",
+			model:   "C-700",
+			setup:   func(s *skreader.SimulatedDevice) {},
+			wantErr: nil,
+		},
+		{
+			name:  "initializing",
+			model: "C-800",
+			setup: func(s *skreader.SimulatedDevice) {
+				s.InitializingDuration = 100 * time.Millisecond
+			},
+			wantErr: nil,
+		},
+		{
+			name:  "ring not low",
+			model: "C-7000",
+			setup: func(s *skreader.SimulatedDevice) {
+				s.Ring = skreader.SkRingStatusHigh
+			},
+			wantErr: skreader.ErrRingNotLow,
+		},
+		{
+			name:  "measuring button pressed",
+			model: "C-7000",
+			setup: func(s *skreader.SimulatedDevice) {
+				s.Button = skreader.SkButtonStatusMeasuring
+			},
+			wantErr: skreader.ErrMeasureButtonPressed,
+		},
+		{
+			name:  "hardware error",
+			model: "C-7000",
+			setup: func(s *skreader.SimulatedDevice) {
+				s.Faults.HardwareError = true
+			},
+			wantErr: skreader.ErrHardware,
+		},
+		{
+			name:  "NAK",
+			model: "C-7000",
+			setup: func(s *skreader.SimulatedDevice) {
+				s.Faults.NAKCommands = []skreader.SkCommand{skreader.SkCommandSetFov}
+			},
+			wantErr: skreader.ErrNAK,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := skreader.NewSimulatedDevice(tt.model)
+			sim.MeasuringDuration = 10 * time.Millisecond
+			tt.setup(sim)
+
+			d, err := skreader.NewDeviceWithAdapter(sim)
+			if err != nil {
+				t.Fatalf("NewDeviceWithAdapter() error = %v", err)
+			}
+
+			m, err := d.Measure()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Measure() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && m.Illuminance.Lux.Str != "407" {
+				t.Errorf("Measure() Lux = %s, want %s", m.Illuminance.Lux.Str, "407")
+			}
+
+			st, err := d.State()
+			if err != nil {
+				t.Fatalf("State() error = %v", err)
+			}
+			if st.Remote != skreader.SkRemoteStatusOff {
+				t.Errorf("State() Remote = %v, want %v", st.Remote, skreader.SkRemoteStatusOff)
+			}
+		})
+	}
+}
+
+func TestSimulatedDeviceMeasureFlash(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		model   string
+		mode    skreader.SkMeasuringMode
+		wantErr bool
+	}{
+		{name: "cordless flash", model: "C-800", mode: skreader.SkMeasuringModeCordlessFlash, wantErr: false},
+		{name: "cord flash", model: "C-7000", mode: skreader.SkMeasuringModeCordFlash, wantErr: false},
+		{name: "not supported", model: "C-700", mode: skreader.SkMeasuringModeCordFlash, wantErr: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			sim := skreader.NewSimulatedDevice(tt.model)
+			sim.MeasuringDuration = 10 * time.Millisecond
+			sim.FlashDelay = 100 * time.Millisecond
+
+			d, err := skreader.NewDeviceWithAdapter(sim)
+			if err != nil {
+				t.Fatalf("NewDeviceWithAdapter() error = %v", err)
+			}
+			d.MeasurementConfig.MeasuringMode = tt.mode
+
+			if _, err = d.MeasureFlash(); (err != nil) != tt.wantErr {
+				t.Errorf("MeasureFlash() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
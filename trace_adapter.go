@@ -0,0 +1,259 @@
+package skreader
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Assert trace adapters implement UsbDevice adapter interface
+var (
+	_ UsbAdapter = (*RecordingAdapter)(nil)
+	_ UsbAdapter = (*ReplayAdapter)(nil)
+)
+
+// Trace operations, one for each UsbAdapter method.
+const (
+	TraceOpOpen         = "open"
+	TraceOpClose        = "close"
+	TraceOpWrite        = "write"
+	TraceOpRead         = "read"
+	TraceOpManufacturer = "manufacturer"
+	TraceOpProduct      = "product"
+)
+
+// ErrReplayMismatch is returned by ReplayAdapter when adapter is used differently than recorded.
+var ErrReplayMismatch = errors.New("replay mismatch")
+
+// TraceEvent represents one recorded UsbAdapter method call.
+// Trace is stored as JSON lines, one event per line.
+type TraceEvent struct {
+	Time time.Time `json:"time"`
+	Op   string    `json:"op"`
+	Data []byte    `json:"data,omitempty"` // written or read data
+	N    int       `json:"n,omitempty"`    // number of bytes written or read
+	Str  string    `json:"str,omitempty"`  // manufacturer or product name
+	Err  string    `json:"err,omitempty"`  // returned error message
+	Code int       `json:"code,omitempty"` // code of sentinel error Err wraps (e.g. ErrTimeout), 0 if none
+}
+
+// RecordingAdapter implements UsbAdapter interface by wrapping another adapter and recording
+// all its method calls to the trace writer. Trace can be played back later with ReplayAdapter.
+//
+// Trace writer is not closed by the adapter. Trace write errors do not affect the device communication,
+// the first one is returned by Err.
+type RecordingAdapter struct {
+	adapter UsbAdapter
+	enc     *json.Encoder
+	mu      sync.Mutex
+	err     error
+}
+
+// NewRecordingAdapter creates RecordingAdapter which records adapter calls to w.
+func NewRecordingAdapter(adapter UsbAdapter, w io.Writer) *RecordingAdapter {
+	return &RecordingAdapter{ //nolint:exhaustruct
+		adapter: adapter,
+		enc:     json.NewEncoder(w),
+	}
+}
+
+func (r *RecordingAdapter) Open() error {
+	err := r.adapter.Open()
+	r.record(TraceEvent{Op: TraceOpOpen}, err) //nolint:exhaustruct
+
+	return err
+}
+
+func (r *RecordingAdapter) Close() error {
+	err := r.adapter.Close()
+	r.record(TraceEvent{Op: TraceOpClose}, err) //nolint:exhaustruct
+
+	return err
+}
+
+func (r *RecordingAdapter) Write(buf []byte) (int, error) {
+	n, err := r.adapter.Write(buf)
+	r.record(TraceEvent{Op: TraceOpWrite, Data: buf, N: n}, err) //nolint:exhaustruct
+
+	return n, err
+}
+
+func (r *RecordingAdapter) Read(buf []byte) (int, error) {
+	n, err := r.adapter.Read(buf)
+	data := buf
+	if n >= 0 && n <= len(buf) {
+		data = buf[:n]
+	}
+	r.record(TraceEvent{Op: TraceOpRead, Data: data, N: n}, err) //nolint:exhaustruct
+
+	return n, err
+}
+
+func (r *RecordingAdapter) Manufacturer() (string, error) {
+	s, err := r.adapter.Manufacturer()
+	r.record(TraceEvent{Op: TraceOpManufacturer, Str: s}, err) //nolint:exhaustruct
+
+	return s, err
+}
+
+func (r *RecordingAdapter) Product() (string, error) {
+	s, err := r.adapter.Product()
+	r.record(TraceEvent{Op: TraceOpProduct, Str: s}, err) //nolint:exhaustruct
+
+	return s, err
+}
+
+// Err returns the first trace write error.
+func (r *RecordingAdapter) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.err
+}
+
+func (r *RecordingAdapter) record(ev TraceEvent, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ev.Time = time.Now()
+	if err != nil {
+		ev.Err = err.Error()
+		ev.Code = sentinelCode(err)
+	}
+
+	if e := r.enc.Encode(ev); e != nil && r.err == nil {
+		r.err = fmt.Errorf("could not write trace: %w", e)
+	}
+}
+
+// ReplayAdapter implements UsbAdapter interface by playing back the trace recorded with RecordingAdapter.
+// Every call must match the next recorded event, written data must be the same as recorded.
+// Otherwise error wrapping ErrReplayMismatch is returned. Recorded timing is not reproduced.
+type ReplayAdapter struct {
+	events []TraceEvent
+	pos    int
+	mu     sync.Mutex
+}
+
+// NewReplayAdapter creates ReplayAdapter which plays back the trace read from r.
+func NewReplayAdapter(r io.Reader) (*ReplayAdapter, error) {
+	var events []TraceEvent
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024) //nolint:gomnd
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var ev TraceEvent
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			return nil, fmt.Errorf("invalid trace event at line %d: %w", line, err)
+		}
+		events = append(events, ev)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read trace: %w", err)
+	}
+
+	return &ReplayAdapter{ //nolint:exhaustruct
+		events: events,
+	}, nil
+}
+
+func (r *ReplayAdapter) Open() error {
+	ev, err := r.next(TraceOpOpen)
+	if err != nil {
+		return err
+	}
+
+	return ev.err()
+}
+
+func (r *ReplayAdapter) Close() error {
+	ev, err := r.next(TraceOpClose)
+	if err != nil {
+		return err
+	}
+
+	return ev.err()
+}
+
+func (r *ReplayAdapter) Write(buf []byte) (int, error) {
+	ev, err := r.next(TraceOpWrite)
+	if err != nil {
+		return 0, err
+	}
+	if !bytes.Equal(ev.Data, buf) {
+		return 0, fmt.Errorf("%w: written %q, recorded %q", ErrReplayMismatch, buf, ev.Data)
+	}
+
+	return ev.N, ev.err()
+}
+
+func (r *ReplayAdapter) Read(buf []byte) (int, error) {
+	ev, err := r.next(TraceOpRead)
+	if err != nil {
+		return 0, err
+	}
+	copy(buf, ev.Data)
+
+	return ev.N, ev.err()
+}
+
+func (r *ReplayAdapter) Manufacturer() (string, error) {
+	ev, err := r.next(TraceOpManufacturer)
+	if err != nil {
+		return "", err
+	}
+
+	return ev.Str, ev.err()
+}
+
+func (r *ReplayAdapter) Product() (string, error) {
+	ev, err := r.next(TraceOpProduct)
+	if err != nil {
+		return "", err
+	}
+
+	return ev.Str, ev.err()
+}
+
+// Remaining returns number of recorded events which were not played back yet.
+func (r *ReplayAdapter) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.events) - r.pos
+}
+
+// next returns the next recorded event if it is of the expected operation.
+func (r *ReplayAdapter) next(op string) (*TraceEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.pos >= len(r.events) {
+		return nil, fmt.Errorf("%w: unexpected %s, trace is over", ErrReplayMismatch, op)
+	}
+
+	ev := &r.events[r.pos]
+	if ev.Op != op {
+		return nil, fmt.Errorf("%w: unexpected %s, recorded %s at event %d", ErrReplayMismatch, op, ev.Op, r.pos+1)
+	}
+	r.pos++
+
+	return ev, nil
+}
+
+// err returns recorded error. It wraps the same sentinel error as the recorded one.
+func (ev *TraceEvent) err() error {
+	if ev.Err == "" {
+		return nil
+	}
+
+	return &codedError{msg: ev.Err, sentinel: sentinelOf(ev.Code)}
+}
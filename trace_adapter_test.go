@@ -0,0 +1,140 @@
+package skreader_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/akares/skreader"
+)
+
+// recordMeasurement records the whole session of single measurement with simulated device.
+func recordMeasurement(t *testing.T, model string) (*skreader.Measurement, []byte) {
+	t.Helper()
+
+	sim := skreader.NewSimulatedDevice(model)
+	sim.MeasuringDuration = 10 * time.Millisecond
+
+	var trace bytes.Buffer
+	rec := skreader.NewRecordingAdapter(sim, &trace)
+
+	d, err := skreader.NewDeviceWithAdapter(rec)
+	if err != nil {
+		t.Fatalf("NewDeviceWithAdapter() error = %v", err)
+	}
+	meas, err := d.Measure()
+	if err != nil {
+		t.Fatalf("Measure() error = %v", err)
+	}
+	if err := d.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := rec.Err(); err != nil {
+		t.Fatalf("RecordingAdapter.Err() = %v", err)
+	}
+
+	return meas, trace.Bytes()
+}
+
+func TestReplayAdapter(t *testing.T) {
+	for _, model := range []string{"C-700", "C-7000"} {
+		t.Run(model, func(t *testing.T) {
+			want, trace := recordMeasurement(t, model)
+
+			replay, err := skreader.NewReplayAdapter(bytes.NewReader(trace))
+			if err != nil {
+				t.Fatalf("NewReplayAdapter() error = %v", err)
+			}
+
+			d, err := skreader.NewDeviceWithAdapter(replay)
+			if err != nil {
+				t.Fatalf("NewDeviceWithAdapter() error = %v", err)
+			}
+			if d.Capabilities().Model != model {
+				t.Errorf("Capabilities().Model = %q, want %q", d.Capabilities().Model, model)
+			}
+			got, err := d.Measure()
+			if err != nil {
+				t.Fatalf("Measure() error = %v", err)
+			}
+			if err := d.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Measure() = %v, want %v", got, want)
+			}
+			if replay.Remaining() != 0 {
+				t.Errorf("Remaining() = %d, want 0", replay.Remaining())
+			}
+		})
+	}
+}
+
+func TestReplayAdapterMismatch(t *testing.T) {
+	_, trace := recordMeasurement(t, "C-7000")
+
+	replay, err := skreader.NewReplayAdapter(bytes.NewReader(trace))
+	if err != nil {
+		t.Fatalf("NewReplayAdapter() error = %v", err)
+	}
+
+	d, err := skreader.NewDeviceWithAdapter(replay)
+	if err != nil {
+		t.Fatalf("NewDeviceWithAdapter() error = %v", err)
+	}
+	d.MeasurementConfig.FieldOfView = skreader.SkFieldOfView10Deg // was not recorded
+
+	_, err = d.Measure()
+	if !errors.Is(err, skreader.ErrReplayMismatch) {
+		t.Errorf("Measure() error = %v, want %v", err, skreader.ErrReplayMismatch)
+	}
+}
+
+func TestReplayAdapterErrors(t *testing.T) {
+	sim := skreader.NewSimulatedDevice("C-7000")
+	sim.Faults.OpenErr = errors.New("device not found")
+
+	var trace bytes.Buffer
+	_, err := skreader.NewDeviceWithAdapter(skreader.NewRecordingAdapter(sim, &trace))
+	if err == nil {
+		t.Fatal("NewDeviceWithAdapter() error = nil, want error")
+	}
+
+	replay, err := skreader.NewReplayAdapter(&trace)
+	if err != nil {
+		t.Fatalf("NewReplayAdapter() error = %v", err)
+	}
+	_, err = skreader.NewDeviceWithAdapter(replay)
+	if err == nil || err.Error() != "device not found" {
+		t.Errorf("NewDeviceWithAdapter() error = %v, want %q", err, "device not found")
+	}
+
+	// Sentinel errors are kept, e.g. to test timeout handling with recorded session.
+	for _, sentinel := range []error{skreader.ErrTimeout, skreader.ErrTransfer} {
+		sim.Faults.OpenErr = nil
+		sim.Faults.ReadErr = fmt.Errorf("%w: IN transfer not completed", sentinel)
+
+		var trace bytes.Buffer
+		rec := skreader.NewRecordingAdapter(sim, &trace)
+		_ = rec.Open()
+		_, recErr := rec.Read(make([]byte, 2))
+
+		replay, err := skreader.NewReplayAdapter(&trace)
+		if err != nil {
+			t.Fatalf("NewReplayAdapter() error = %v", err)
+		}
+		_ = replay.Open()
+		_, err = replay.Read(make([]byte, 2))
+		if !errors.Is(err, sentinel) || err.Error() != recErr.Error() {
+			t.Errorf("Read() error = %v, want %v wrapping %v", err, recErr, sentinel)
+		}
+	}
+
+	if _, err := skreader.NewReplayAdapter(bytes.NewBufferString("{invalid\n")); err == nil {
+		t.Error("NewReplayAdapter() error = nil, want error")
+	}
+}
@@ -0,0 +1,60 @@
+package skreader
+
+import (
+	"log"
+	"time"
+)
+
+// Tracer receives events of communication with device, e.g. to find out why some device misbehaves.
+// Set it with WithTracer option. Methods are called synchronously, TraceCommand with command lock held,
+// so they must return quickly and must not call Device methods.
+type Tracer interface {
+	TraceCommand(ev CommandTrace)
+	TracePoll(ev PollTrace)
+}
+
+// CommandTrace describes one command sent to device and its responses.
+type CommandTrace struct {
+	Cmd      SkCommand
+	Ack      []byte        // acknowledge response, nil if not received
+	Response int           // size of main response in bytes, 0 if not received
+	Latency  time.Duration // from sending command to receiving main response or error
+	Err      error
+}
+
+// PollTrace describes one iteration of polling device status while waiting for it to be ready.
+type PollTrace struct {
+	Iteration int           // starting from 1
+	Elapsed   time.Duration // since waiting started
+	State     *DeviceState  // nil if status could not be read
+	Err       error
+}
+
+// LogTracer is Tracer which prints all events to logger.
+type LogTracer struct {
+	Logger *log.Logger
+}
+
+// NewLogTracer creates LogTracer printing to logger.
+func NewLogTracer(logger *log.Logger) *LogTracer {
+	return &LogTracer{Logger: logger}
+}
+
+func (t *LogTracer) TraceCommand(ev CommandTrace) {
+	if ev.Err != nil {
+		t.Logger.Printf("%s: ack [% x], %d bytes response, %s: %v", ev.Cmd, ev.Ack, ev.Response, ev.Latency, ev.Err)
+
+		return
+	}
+	t.Logger.Printf("%s: ack [% x], %d bytes response, %s", ev.Cmd, ev.Ack, ev.Response, ev.Latency)
+}
+
+func (t *LogTracer) TracePoll(ev PollTrace) {
+	if ev.State == nil {
+		t.Logger.Printf("poll #%d after %s: %v", ev.Iteration, ev.Elapsed, ev.Err)
+
+		return
+	}
+	t.Logger.Printf("poll #%d after %s: status %d, remote %d, button %d, ring %d",
+		ev.Iteration, ev.Elapsed, ev.State.Status, ev.State.Remote, ev.State.Button, ev.State.Ring)
+}
@@ -0,0 +1,113 @@
+package skreader_test
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/akares/skreader"
+)
+
+// recordingTracer keeps all traced events.
+type recordingTracer struct {
+	commands []skreader.CommandTrace
+	polls    []skreader.PollTrace
+}
+
+func (r *recordingTracer) TraceCommand(ev skreader.CommandTrace) { r.commands = append(r.commands, ev) }
+func (r *recordingTracer) TracePoll(ev skreader.PollTrace)       { r.polls = append(r.polls, ev) }
+
+func TestTracer(t *testing.T) {
+	sim := skreader.NewSimulatedDevice("C-7000")
+	sim.MeasuringDuration = 20 * time.Millisecond
+	tracer := &recordingTracer{}
+
+	d, err := skreader.NewDevice(sim, skreader.WithTracer(tracer), skreader.WithPollInterval(5*time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewDevice() error = %v", err)
+	}
+	defer d.Close()
+
+	if _, err = d.Measure(); err != nil {
+		t.Fatalf("Measure() error = %v", err)
+	}
+
+	cmds := make([]string, 0, len(tracer.commands))
+	for _, ev := range tracer.commands {
+		cmds = append(cmds, string(ev.Cmd))
+		assert.Equal(t, skreader.SkResponseOK, ev.Ack, "%s ack invalid", ev.Cmd)
+		assert.Nil(t, ev.Err, "%s error invalid", ev.Cmd)
+	}
+	assert.Equal(t, []string{"MN", "FV", "ST", "RT1"}, cmds[:4], "first commands invalid")
+	assert.Equal(t, []string{"RM0"}, filterCommands(cmds, "RM0"), "RM0 commands invalid")
+
+	last := tracer.commands[len(tracer.commands)-1]
+	assert.Equal(t, skreader.SkCommandSetRemoteOff, last.Cmd, "last command invalid")
+	nr := tracer.commands[len(tracer.commands)-2]
+	assert.Equal(t, skreader.SkCommandGetMeasurementResult, nr.Cmd, "measurement result command invalid")
+	assert.GreaterOrEqual(t, nr.Response, skreader.MeasurementDataValidSize, "measurement result size invalid")
+
+	// Waiting for device to start measuring and to end measuring.
+	if assert.GreaterOrEqual(t, len(tracer.polls), 3, "polls invalid") {
+		assert.Equal(t, 1, tracer.polls[0].Iteration, "first poll iteration invalid")
+		assert.Equal(t, 1, tracer.polls[1].Iteration, "first measuring poll iteration invalid")
+		assert.Equal(t, skreader.SkDeviceStatusBusyMeasuring, tracer.polls[1].State.Status, "measuring poll status invalid")
+	}
+}
+
+func TestTracerError(t *testing.T) {
+	sim := skreader.NewSimulatedDevice("C-7000")
+	tracer := &recordingTracer{}
+
+	d, err := skreader.NewDevice(sim, skreader.WithTracer(tracer))
+	if err != nil {
+		t.Fatalf("NewDevice() error = %v", err)
+	}
+	defer d.Close()
+
+	// Measuring can't be started in normal control mode.
+	err = d.StartMeasuring()
+	if !errors.Is(err, skreader.ErrNAK) {
+		t.Fatalf("StartMeasuring() error = %v, want %v", err, skreader.ErrNAK)
+	}
+
+	ev := tracer.commands[len(tracer.commands)-1]
+	assert.Equal(t, skreader.SkCommandStartMeasuring, ev.Cmd, "command invalid")
+	assert.Equal(t, 0, ev.Response, "response size invalid")
+	assert.ErrorIs(t, ev.Err, skreader.ErrNAK, "error invalid")
+}
+
+func TestLogTracer(t *testing.T) {
+	var buf bytes.Buffer
+	tracer := skreader.NewLogTracer(log.New(&buf, "", 0))
+
+	tracer.TraceCommand(skreader.CommandTrace{
+		Cmd:      skreader.SkCommandGetStatus,
+		Ack:      skreader.SkResponseOK,
+		Response: 5,
+		Latency:  time.Millisecond,
+		Err:      nil,
+	})
+	tracer.TracePoll(skreader.PollTrace{Iteration: 2, Elapsed: 100 * time.Millisecond, State: nil, Err: skreader.ErrTransfer})
+
+	assert.Equal(t, []string{
+		"ST: ack [06 30], 5 bytes response, 1ms",
+		"poll #2 after 100ms: USB transfer error",
+	}, strings.Split(strings.TrimSpace(buf.String()), "\n"), "log invalid")
+}
+
+func filterCommands(cmds []string, cmd string) []string {
+	var res []string
+	for _, c := range cmds {
+		if c == cmd {
+			res = append(res, c)
+		}
+	}
+
+	return res
+}
@@ -1,6 +1,20 @@
 package skreader
 
-import "io"
+import (
+	"io"
+	"time"
+)
+
+const (
+	IDVendor  = 0x0A41
+	IDProduct = 0x7003
+
+	EndpointNumOut = 0x02
+	EndpointNumIn  = 0x81
+
+	UsbReadTimeoutDefault  = time.Duration(5) * time.Second // how long to wait for one IN transfer
+	UsbWriteTimeoutDefault = time.Duration(5) * time.Second // how long to wait for one OUT transfer
+)
 
 // UsbAdapter is an interface that used to abstract USB communication.
 // It is used by SEKONIC Device handler to communicate with device.
@@ -26,3 +40,28 @@ type UsbAdapterDescriber interface {
 	Manufacturer() (string, error) // Manufacturer must return device manufacturer name or empty string or an error.
 	Product() (string, error)      // Product must return device product name or empty string or an error.
 }
+
+// UsbAdapterOptions represents settings of USB adapters connecting to real device
+// (GousbAdapter, UsbfsAdapter). Zero values mean defaults.
+type UsbAdapterOptions struct {
+	// ReadTimeout limits one IN transfer, so that disconnected or not responding device
+	// does not block forever. UsbReadTimeoutDefault is used if 0.
+	ReadTimeout time.Duration
+	// WriteTimeout limits one OUT transfer. UsbWriteTimeoutDefault is used if 0.
+	WriteTimeout time.Duration
+	// Serial selects the device with this serial number if there are several connected, see ListDevices.
+	Serial string
+	// Path selects the device connected to this USB port, e.g. "1-2.3" (bus 1, port 3 of the hub
+	// connected to port 2), see ListDevices. First found device is used if neither Serial nor Path is set.
+	Path string
+}
+
+// UsbDeviceInfo describes connected SEKONIC device found by ListDevices.
+type UsbDeviceInfo struct {
+	Bus          int
+	Address      int
+	Path         string // USB port path, e.g. "1-2.3", see UsbAdapterOptions
+	Serial       string
+	Manufacturer string
+	Product      string
+}
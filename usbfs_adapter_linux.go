@@ -0,0 +1,382 @@
+package skreader
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf16"
+	"unsafe"
+)
+
+var _ UsbAdapter = (*UsbfsAdapter)(nil) // assert it implements UsbAdapter interface
+
+// UsbfsAdapter implements UsbAdapter interface directly over Linux usbfs (/dev/bus/usb) ioctls.
+// Unlike GousbAdapter, it needs neither cgo nor libusb, so it can be used in static builds.
+// Devices are found using sysfs (/sys/bus/usb/devices).
+//
+// It is used by default if package is built without cgo or with `usbfs` build tag.
+// Zero value uses default options, use NewUsbfsAdapter to set them.
+type UsbfsAdapter struct {
+	opts UsbAdapterOptions
+
+	file *os.File
+	desc []byte // device descriptor
+}
+
+// NewUsbfsAdapter creates UsbfsAdapter with given options.
+func NewUsbfsAdapter(opts UsbAdapterOptions) *UsbfsAdapter {
+	return &UsbfsAdapter{opts: opts} //nolint:exhaustruct
+}
+
+// Expose file system locations and usbfs requests as variables to be able to mock them in tests.
+var (
+	UsbfsSysfsDir = "/sys/bus/usb/devices"
+	UsbfsDevDir   = "/dev/bus/usb"
+
+	UsbfsClaimInterface = func(fd uintptr, intf uint32) error {
+		_, err := usbfsIoctl(fd, usbdevfsClaimInterface, unsafe.Pointer(&intf))
+
+		return err
+	}
+	UsbfsReleaseInterface = func(fd uintptr, intf uint32) error {
+		_, err := usbfsIoctl(fd, usbdevfsReleaseInterface, unsafe.Pointer(&intf))
+
+		return err
+	}
+	UsbfsBulk = func(fd uintptr, ep uint8, buf []byte, timeout time.Duration) (int, error) {
+		if len(buf) == 0 {
+			return 0, nil
+		}
+		xfer := usbdevfsBulkTransfer{
+			ep:      uint32(ep),
+			len:     uint32(len(buf)),
+			timeout: usbfsTimeout(timeout),
+			data:    unsafe.Pointer(&buf[0]),
+		}
+		n, err := usbfsIoctl(fd, usbdevfsBulk, unsafe.Pointer(&xfer))
+		runtime.KeepAlive(buf)
+
+		return n, err
+	}
+	UsbfsControl = func(fd uintptr, reqType, req uint8, value, index uint16, buf []byte, timeout time.Duration) (int, error) {
+		if len(buf) == 0 {
+			return 0, errors.New("empty control transfer buffer")
+		}
+		xfer := usbdevfsCtrlTransfer{
+			bRequestType: reqType,
+			bRequest:     req,
+			wValue:       value,
+			wIndex:       index,
+			wLength:      uint16(len(buf)),
+			timeout:      usbfsTimeout(timeout),
+			data:         unsafe.Pointer(&buf[0]),
+		}
+		n, err := usbfsIoctl(fd, usbdevfsControl, unsafe.Pointer(&xfer))
+		runtime.KeepAlive(buf)
+
+		return n, err
+	}
+)
+
+// Kernel usbfs interface, see linux/usbdevice_fs.h.
+
+type usbdevfsCtrlTransfer struct {
+	bRequestType uint8
+	bRequest     uint8
+	wValue       uint16
+	wIndex       uint16
+	wLength      uint16
+	timeout      uint32 // in milliseconds
+	data         unsafe.Pointer
+}
+
+type usbdevfsBulkTransfer struct {
+	ep      uint32
+	len     uint32
+	timeout uint32 // in milliseconds
+	data    unsafe.Pointer
+}
+
+const (
+	iocWrite = 1
+	iocRead  = 2
+)
+
+var (
+	usbdevfsControl          = usbfsIoc(iocRead|iocWrite, 0, unsafe.Sizeof(usbdevfsCtrlTransfer{})) //nolint:exhaustruct
+	usbdevfsBulk             = usbfsIoc(iocRead|iocWrite, 2, unsafe.Sizeof(usbdevfsBulkTransfer{})) //nolint:exhaustruct
+	usbdevfsClaimInterface   = usbfsIoc(iocRead, 15, unsafe.Sizeof(uint32(0)))
+	usbdevfsReleaseInterface = usbfsIoc(iocRead, 16, unsafe.Sizeof(uint32(0)))
+)
+
+// usbfsIoc returns ioctl request number like _IOC macro does.
+func usbfsIoc(dir, nr, size uintptr) uintptr {
+	return dir<<30 | size<<16 | 'U'<<8 | nr
+}
+
+func usbfsIoctl(fd, req uintptr, arg unsafe.Pointer) (int, error) {
+	r, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, req, uintptr(arg))
+	if errno != 0 {
+		return 0, errno
+	}
+
+	return int(r), nil
+}
+
+// usbfsTimeout converts timeout to milliseconds, 0 would mean no timeout for usbfs.
+func usbfsTimeout(timeout time.Duration) uint32 {
+	if ms := timeout.Milliseconds(); ms > 0 {
+		return uint32(ms)
+	}
+
+	return 1
+}
+
+// Standard USB requests and descriptors used by the adapter.
+const (
+	usbRequestTypeIn        = 0x80
+	usbRequestGetDescriptor = 0x06
+	usbDescTypeString       = 0x03
+
+	usbDeviceDescSize         = 18
+	usbDeviceDescManufacturer = 14 // iManufacturer string index position
+	usbDeviceDescProduct      = 15 // iProduct string index position
+
+	usbStringDescMaxSize = 255
+)
+
+func (u *UsbfsAdapter) Open() error {
+	dev, err := u.find()
+	if err != nil {
+		return err
+	}
+	if dev == nil {
+		return errors.New("could not open a device, is it connected?")
+	}
+
+	f, err := os.OpenFile(usbfsDevNode(dev), os.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("could not open a device: %v", err)
+	}
+
+	// Reading device node returns device descriptor followed by configuration descriptors.
+	desc := make([]byte, usbDeviceDescSize)
+	if _, err = io.ReadFull(f, desc); err != nil {
+		_ = f.Close()
+
+		return fmt.Errorf("could not read device descriptor: %v", err)
+	}
+
+	if err = UsbfsClaimInterface(f.Fd(), 0); err != nil {
+		_ = f.Close()
+
+		return fmt.Errorf("could not claim interface: %v", err)
+	}
+
+	u.file = f
+	u.desc = desc
+
+	return nil
+}
+
+// find returns the device selected by Serial and Path options, first found device if they are not set
+// or nil if there is no such device.
+func (u *UsbfsAdapter) find() (*UsbDeviceInfo, error) {
+	devs, err := ListUsbfsDevices()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range devs {
+		dev := &devs[i]
+		if u.opts.Path != "" && dev.Path != u.opts.Path {
+			continue
+		}
+		if u.opts.Serial != "" && dev.Serial != u.opts.Serial {
+			continue
+		}
+
+		return dev, nil
+	}
+
+	return nil, nil
+}
+
+// Close releases the device. It is safe to call it more than once, adapter can be opened again then.
+func (u *UsbfsAdapter) Close() error {
+	if u.file == nil {
+		return nil
+	}
+
+	_ = UsbfsReleaseInterface(u.file.Fd(), 0)
+	err := u.file.Close()
+	u.file = nil
+
+	return err
+}
+
+// Read reads data from IN endpoint. If transfer is not completed within read timeout,
+// error wrapping ErrTimeout is returned.
+func (u *UsbfsAdapter) Read(buf []byte) (int, error) {
+	timeout := u.opts.ReadTimeout
+	if timeout <= 0 {
+		timeout = UsbReadTimeoutDefault
+	}
+
+	if u.file == nil {
+		return 0, errors.New("device is not open")
+	}
+
+	n, err := UsbfsBulk(u.file.Fd(), EndpointNumIn, buf, timeout)
+	if errors.Is(err, syscall.ETIMEDOUT) {
+		return n, fmt.Errorf("%w: IN transfer not completed in %s", ErrTimeout, timeout)
+	}
+
+	return n, err
+}
+
+// Write writes data to OUT endpoint. If transfer is not completed within write timeout,
+// error wrapping ErrTimeout is returned.
+func (u *UsbfsAdapter) Write(buf []byte) (int, error) {
+	timeout := u.opts.WriteTimeout
+	if timeout <= 0 {
+		timeout = UsbWriteTimeoutDefault
+	}
+
+	if u.file == nil {
+		return 0, errors.New("device is not open")
+	}
+
+	n, err := UsbfsBulk(u.file.Fd(), EndpointNumOut, buf, timeout)
+	if errors.Is(err, syscall.ETIMEDOUT) {
+		return n, fmt.Errorf("%w: OUT transfer not completed in %s", ErrTimeout, timeout)
+	}
+
+	return n, err
+}
+
+func (u *UsbfsAdapter) Manufacturer() (string, error) {
+	manufacturer, err := u.stringDescriptor(usbDeviceDescManufacturer)
+	if err != nil {
+		return "", fmt.Errorf("could not read Manufacturer: %v", err)
+	}
+
+	return manufacturer, nil
+}
+
+func (u *UsbfsAdapter) Product() (string, error) {
+	product, err := u.stringDescriptor(usbDeviceDescProduct)
+	if err != nil {
+		return "", fmt.Errorf("could not read Product: %v", err)
+	}
+
+	return product, nil
+}
+
+// stringDescriptor requests the string which index is stored at given position of device descriptor.
+// The first language supported by device is used.
+func (u *UsbfsAdapter) stringDescriptor(descPos int) (string, error) {
+	if u.file == nil {
+		return "", errors.New("device is not open")
+	}
+	index := u.desc[descPos]
+	if index == 0 {
+		return "", nil // device has no such string
+	}
+
+	timeout := u.opts.ReadTimeout
+	if timeout <= 0 {
+		timeout = UsbReadTimeoutDefault
+	}
+
+	// String descriptor 0 contains the list of supported language IDs.
+	buf := make([]byte, usbStringDescMaxSize)
+	n, err := UsbfsControl(u.file.Fd(), usbRequestTypeIn, usbRequestGetDescriptor, usbDescTypeString<<8, 0, buf, timeout)
+	if err != nil {
+		return "", err
+	}
+	if n < 4 || buf[1] != usbDescTypeString {
+		return "", errors.New("invalid language IDs descriptor")
+	}
+	lang := uint16(buf[2]) | uint16(buf[3])<<8
+
+	value := uint16(usbDescTypeString)<<8 | uint16(index)
+	n, err = UsbfsControl(u.file.Fd(), usbRequestTypeIn, usbRequestGetDescriptor, value, lang, buf, timeout)
+	if err != nil {
+		return "", err
+	}
+	if n < 2 || buf[0] < 2 || int(buf[0]) > n || buf[1] != usbDescTypeString {
+		return "", errors.New("invalid string descriptor")
+	}
+
+	// String is UTF-16LE encoded.
+	chars := make([]uint16, 0, int(buf[0])/2)
+	for i := 2; i+1 < int(buf[0]); i += 2 {
+		chars = append(chars, uint16(buf[i])|uint16(buf[i+1])<<8)
+	}
+
+	return string(utf16.Decode(chars)), nil
+}
+
+// ListUsbfsDevices returns all connected SEKONIC devices found in sysfs.
+func ListUsbfsDevices() ([]UsbDeviceInfo, error) {
+	entries, err := os.ReadDir(UsbfsSysfsDir)
+	if err != nil {
+		return nil, fmt.Errorf("could not list devices: %v", err)
+	}
+
+	var devs []UsbDeviceInfo
+	for _, entry := range entries {
+		dir := filepath.Join(UsbfsSysfsDir, entry.Name())
+
+		vid, err := strconv.ParseUint(readSysfsAttr(dir, "idVendor"), 16, 16)
+		if err != nil || vid != IDVendor {
+			continue // not a device (e.g. interface) or not SEKONIC device
+		}
+		pid, err := strconv.ParseUint(readSysfsAttr(dir, "idProduct"), 16, 16)
+		if err != nil || pid != IDProduct {
+			continue
+		}
+		bus, err := strconv.Atoi(readSysfsAttr(dir, "busnum"))
+		if err != nil {
+			continue
+		}
+		addr, err := strconv.Atoi(readSysfsAttr(dir, "devnum"))
+		if err != nil {
+			continue
+		}
+
+		devs = append(devs, UsbDeviceInfo{
+			Bus:          bus,
+			Address:      addr,
+			Path:         entry.Name(), // sysfs device names are port paths, e.g. "1-2.3"
+			Serial:       readSysfsAttr(dir, "serial"),
+			Manufacturer: readSysfsAttr(dir, "manufacturer"),
+			Product:      readSysfsAttr(dir, "product"),
+		})
+	}
+
+	return devs, nil
+}
+
+// readSysfsAttr returns value of sysfs attribute file or empty string if it can't be read.
+func readSysfsAttr(dir, name string) string {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(data))
+}
+
+// usbfsDevNode returns path of the usbfs device node, e.g. /dev/bus/usb/001/005.
+func usbfsDevNode(dev *UsbDeviceInfo) string {
+	return filepath.Join(UsbfsDevDir, fmt.Sprintf("%03d", dev.Bus), fmt.Sprintf("%03d", dev.Address))
+}
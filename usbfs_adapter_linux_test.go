@@ -0,0 +1,173 @@
+package skreader_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+	"unicode/utf16"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/akares/skreader"
+)
+
+// fakeUsbfs creates sysfs and usbfs trees with two SEKONIC devices and one device of another vendor.
+// Transfers to the SEKONIC devices are served by sim.
+func fakeUsbfs(t *testing.T, sim *skreader.SimulatedDevice) {
+	t.Helper()
+
+	root := t.TempDir()
+	sysfs := filepath.Join(root, "sys")
+	devfs := filepath.Join(root, "dev")
+
+	writeFile := func(path string, data []byte) {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, dev := range []struct {
+		name, vid, pid, bus, addr, serial string
+	}{
+		{name: "1-2.3", vid: "0a41", pid: "7003", bus: "1", addr: "5", serial: "SN0001"},
+		{name: "1-4", vid: "046d", pid: "c52b", bus: "1", addr: "2", serial: "LOGI"},
+		{name: "2-1", vid: "0a41", pid: "7003", bus: "2", addr: "7", serial: "SN0002"},
+	} {
+		dir := filepath.Join(sysfs, dev.name)
+		writeFile(filepath.Join(dir, "idVendor"), []byte(dev.vid+"\n"))
+		writeFile(filepath.Join(dir, "idProduct"), []byte(dev.pid+"\n"))
+		writeFile(filepath.Join(dir, "busnum"), []byte(dev.bus+"\n"))
+		writeFile(filepath.Join(dir, "devnum"), []byte(dev.addr+"\n"))
+		writeFile(filepath.Join(dir, "serial"), []byte(dev.serial+"\n"))
+		writeFile(filepath.Join(dir, "manufacturer"), []byte("SEKONIC\n"))
+		writeFile(filepath.Join(dir, "product"), []byte("C-7000\n"))
+
+		// Device descriptor with manufacturer and product string indexes 1 and 2.
+		desc := []byte{18, 1, 0, 2, 0, 0, 0, 64, 0x41, 0x0a, 0x03, 0x70, 0, 1, 1, 2, 3, 1}
+		writeFile(filepath.Join(devfs, "00"+dev.bus, "00"+dev.addr), desc)
+	}
+	// Interfaces are listed in sysfs too.
+	writeFile(filepath.Join(sysfs, "1-2.3:1.0", "bInterfaceNumber"), []byte("00\n"))
+
+	sysfsDir, devDir := skreader.UsbfsSysfsDir, skreader.UsbfsDevDir
+	claim, release := skreader.UsbfsClaimInterface, skreader.UsbfsReleaseInterface
+	bulk, control := skreader.UsbfsBulk, skreader.UsbfsControl
+	t.Cleanup(func() {
+		skreader.UsbfsSysfsDir, skreader.UsbfsDevDir = sysfsDir, devDir
+		skreader.UsbfsClaimInterface, skreader.UsbfsReleaseInterface = claim, release
+		skreader.UsbfsBulk, skreader.UsbfsControl = bulk, control
+	})
+
+	skreader.UsbfsSysfsDir = sysfs
+	skreader.UsbfsDevDir = devfs
+	skreader.UsbfsClaimInterface = func(_ uintptr, _ uint32) error { return nil }
+	skreader.UsbfsReleaseInterface = func(_ uintptr, _ uint32) error { return nil }
+	skreader.UsbfsBulk = func(_ uintptr, ep uint8, buf []byte, _ time.Duration) (int, error) {
+		if ep == skreader.EndpointNumOut {
+			return sim.Write(buf)
+		}
+
+		return sim.Read(buf)
+	}
+	skreader.UsbfsControl = func(_ uintptr, _, _ uint8, value, _ uint16, buf []byte, _ time.Duration) (int, error) {
+		strs := map[uint16]string{0x301: sim.ManufacturerName, 0x302: sim.ProductName}
+		if value == 0x300 {
+			return copy(buf, []byte{4, 3, 0x09, 0x04}), nil // en-US
+		}
+		str, ok := strs[value]
+		if !ok {
+			return 0, syscall.EPIPE
+		}
+		desc := []byte{0, 3}
+		for _, c := range utf16.Encode([]rune(str)) {
+			desc = append(desc, byte(c), byte(c>>8))
+		}
+		desc[0] = byte(len(desc))
+
+		return copy(buf, desc), nil
+	}
+}
+
+func TestListUsbfsDevices(t *testing.T) {
+	fakeUsbfs(t, skreader.NewSimulatedDevice("C-7000"))
+
+	devs, err := skreader.ListUsbfsDevices()
+	assert.Nil(t, err, "ListUsbfsDevices() error")
+	assert.Equal(t, []skreader.UsbDeviceInfo{
+		{Bus: 1, Address: 5, Path: "1-2.3", Serial: "SN0001", Manufacturer: "SEKONIC", Product: "C-7000"},
+		{Bus: 2, Address: 7, Path: "2-1", Serial: "SN0002", Manufacturer: "SEKONIC", Product: "C-7000"},
+	}, devs, "ListUsbfsDevices() invalid")
+}
+
+func TestUsbfsAdapterMeasure(t *testing.T) {
+	sim := skreader.NewSimulatedDevice("C-7000")
+	sim.MeasuringDuration = 10 * time.Millisecond
+	sim.MaxTransferSize = 512
+	fakeUsbfs(t, sim)
+
+	d, err := skreader.NewDeviceWithAdapter(skreader.NewUsbfsAdapter(skreader.UsbAdapterOptions{})) //nolint:exhaustruct
+	if err != nil {
+		t.Fatalf("NewDeviceWithAdapter() error = %v", err)
+	}
+	defer d.Close()
+
+	assert.Equal(t, "SEKONIC C-7000", d.String(), "String() invalid")
+
+	m, err := d.Measure()
+	if err != nil {
+		t.Fatalf("Measure() error = %v", err)
+	}
+	assert.Equal(t, "407", m.Illuminance.Lux.Str, "Measure() Lux invalid")
+}
+
+func TestUsbfsAdapterSelectDevice(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		opts    skreader.UsbAdapterOptions
+		wantErr bool
+	}{
+		{name: "first", opts: skreader.UsbAdapterOptions{}, wantErr: false},                                                  //nolint:exhaustruct
+		{name: "by serial", opts: skreader.UsbAdapterOptions{Serial: "SN0002"}, wantErr: false},                              //nolint:exhaustruct
+		{name: "by path", opts: skreader.UsbAdapterOptions{Path: "1-2.3"}, wantErr: false},                                   //nolint:exhaustruct
+		{name: "unknown serial", opts: skreader.UsbAdapterOptions{Serial: "SN0003"}, wantErr: true},                          //nolint:exhaustruct
+		{name: "other vendor path", opts: skreader.UsbAdapterOptions{Path: "1-4"}, wantErr: true},                            //nolint:exhaustruct
+		{name: "serial and path mismatch", opts: skreader.UsbAdapterOptions{Serial: "SN0002", Path: "1-2.3"}, wantErr: true}, //nolint:exhaustruct
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeUsbfs(t, skreader.NewSimulatedDevice("C-7000"))
+
+			u := skreader.NewUsbfsAdapter(tt.opts)
+			if err := u.Open(); (err != nil) != tt.wantErr {
+				t.Fatalf("Open() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			assert.Nil(t, u.Close(), "Close() error")
+		})
+	}
+}
+
+func TestUsbfsAdapterTimeout(t *testing.T) {
+	fakeUsbfs(t, skreader.NewSimulatedDevice("C-7000"))
+	skreader.UsbfsBulk = func(_ uintptr, _ uint8, _ []byte, _ time.Duration) (int, error) {
+		return 0, syscall.ETIMEDOUT
+	}
+
+	u := skreader.NewUsbfsAdapter(skreader.UsbAdapterOptions{ReadTimeout: time.Second}) //nolint:exhaustruct
+	if err := u.Open(); err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer u.Close()
+
+	_, err := u.Read(make([]byte, 2))
+	if !errors.Is(err, skreader.ErrTimeout) {
+		t.Errorf("Read() error = %v, want %v", err, skreader.ErrTimeout)
+	}
+	_, err = u.Write([]byte("ST"))
+	if !errors.Is(err, skreader.ErrTimeout) {
+		t.Errorf("Write() error = %v, want %v", err, skreader.ErrTimeout)
+	}
+}